@@ -36,8 +36,11 @@ import (
 //	}
 //
 // This will read the MODE, REST_HOST and REST_PORT environment variables into the Mode, HTTP.Host and HTTP.Port fields of the cfg variable.
-func ReadEnv(cfg any) error {
-	return env.Read(cfg)
+//
+// Fields without an env tag are read from the environment variable derived from their canonical key,
+// see WithNaming.
+func ReadEnv(cfg any, opts ...Option) error {
+	return env.Read(cfg, internalOptions(opts)...)
 }
 
 // MustReadEnv is similar to ReadEnv but panics if the reading process fails.
@@ -64,8 +67,8 @@ func ReadEnv(cfg any) error {
 //
 // This will read the MODE, HTTP_HOST and HTTP_PORT environment variables into the Mode, HTTP.Host and HTTP.Port fields of the cfg variable.
 // If any of these environment variables are not set, the program will panic.
-func MustReadEnv(cfg any) {
-	if err := ReadEnv(cfg); err != nil {
+func MustReadEnv(cfg any, opts ...Option) {
+	if err := ReadEnv(cfg, opts...); err != nil {
 		panic(err)
 	}
 }
@@ -97,8 +100,10 @@ func MustReadEnv(cfg any) {
 //
 // This will read the command-line flags --mode, --http-host and --http-port (or -m, -hh and -hp respectively) into the Mode, HTTP.Host and HTTP.Port fields of the cfg variable.
 // If any of these flags are not set, the function will return an error.
-func ReadFlag(cfg any) error {
-	return flag.Read(cfg)
+//
+// Fields without a flag tag are read from the flag derived from their canonical key, see WithNaming.
+func ReadFlag(cfg any, opts ...Option) error {
+	return flag.Read(cfg, internalOptions(opts)...)
 }
 
 // MustReadFlag is similar to ReadFlag but panics if the reading process fails.
@@ -125,8 +130,8 @@ func ReadFlag(cfg any) error {
 //
 // This will read the command-line flags --mode, --http-host and --http-port (or -m, -hh and -hp respectively) into the Mode, HTTP.Host and HTTP.Port fields of the cfg variable.
 // If any of these flags are not set, the program will panic.
-func MustReadFlag(cfg any) {
-	if err := ReadFlag(cfg); err != nil {
+func MustReadFlag(cfg any, opts ...Option) {
+	if err := ReadFlag(cfg, opts...); err != nil {
 		panic(err)
 	}
 }
@@ -159,8 +164,37 @@ func MustReadFlag(cfg any) {
 //
 // This will read the mode, http_host and http_port configuration options from the config.yaml file into the Mode, HTTP.Host and HTTP.Port fields of the cfg variable.
 // If any of these configuration options are not set in the file, the function will return an error.
-func ReadFile(path string, cfg any) error {
-	return file.Read(path, cfg)
+//
+// The format of the file is determined by its extension: .json, .json5 or .jsonc, .yaml or .yml, .toml,
+// .hcl, .ini, .properties, .xml and .env. The keys are mapped to the fields with the json, yaml, toml, hcl,
+// ini, properties, xml and env tags respectively, falling back to the canonical key of the field, see
+// WithNaming. The values of INI, .properties, XML and .env files are converted to the types of the fields
+// the same way. HCL files are read with the HCL 1 decoder, which supports attributes, blocks and lists, but
+// not the expressions, functions, for loops and typed blocks of HCL 2. JSON5 and JSONC files are decoded as
+// JSON, but may contain comments, trailing commas, unquoted keys, single-quoted strings with line
+// continuations, hexadecimal numbers and numbers with a plus sign or a leading or trailing decimal point.
+// Infinity and NaN are rejected, since JSON cannot represent them. Jsonnet files are evaluated into JSON
+// first, with the environment variables and flags of the fields available as external variables, e.g.
+// std.extVar("HTTP_PORT"). In XML files both the child elements and the attributes of an element map to
+// the fields of the nested struct.
+//
+// A file may include other files by listing them under the top-level "$include" key:
+//
+//	$include: [db.yaml, cache.toml]
+//	mode: prod
+//
+// XML files list the included files in an include processing instruction: <?include db.xml, cache.toml?>.
+// Included files are resolved relative to the including file and are read before it, so the values
+// of the including file take precedence. Include cycles are reported as an error, and errors in included
+// files show the chain of files that led to them.
+//
+// With a profile selected by WithProfile or WithProfileFrom, the section of the profile under the top-level
+// profiles key is read over every file, and the file of the profile, e.g. config.prod.yaml, is read over
+// the base file if it exists.
+//
+// The behavior can be changed with options, for example WithStrict rejects unknown keys.
+func ReadFile(path string, cfg any, opts ...Option) error {
+	return file.Read(path, cfg, internalOptions(opts)...)
 }
 
 // MustReadFile is similar to ReadFile but panics if the reading process fails.
@@ -187,8 +221,8 @@ func ReadFile(path string, cfg any) error {
 //
 // This will read the mode, http_host and http_port configuration options from the config.yaml file into the Mode, HTTP.Host and HTTP.Port fields of the cfg variable.
 // If any of these configuration options are not set in the file, the program will panic.
-func MustReadFile(path string, cfg any) {
-	if err := ReadFile(path, cfg); err != nil {
+func MustReadFile(path string, cfg any, opts ...Option) {
+	if err := ReadFile(path, cfg, opts...); err != nil {
 		panic(err)
 	}
 }
@@ -0,0 +1,46 @@
+package gocfg
+
+import (
+	"github.com/dsbasko/go-cfg/internal/flag"
+)
+
+var (
+	// ErrUnsupportedShell is the error of GenerateCompletion for a shell it cannot write the script for.
+	ErrUnsupportedShell = flag.ErrUnsupportedShell
+
+	// ErrInvalidPathTag is the error of GenerateCompletion for a path tag other than file or dir.
+	ErrInvalidPathTag = flag.ErrInvalidPathTag
+)
+
+// GenerateCompletion is a function that generates the completion script of the program for the shell, which is
+// bash, zsh or fish. The script completes the command-line flags ReadFlag reads into the provided cfg structure,
+// long and short, including the profile flag set with WithProfileFrom, and their values:
+//
+//	validate:"oneof=a b c"  the values of the oneof rule
+//	bool fields             true and false
+//	path:"file"             the paths of files
+//	path:"dir"              the paths of directories
+//
+// The values of the cfg structure itself are not used. The function returns ErrUnsupportedShell for other shells
+// and ErrInvalidPathTag for other values of the path tag.
+//
+// Example:
+//
+//	type Config struct {
+//		Mode   string `flag:"mode" s-flag:"m" validate:"oneof=dev prod" description:"Application mode"`
+//		Config string `flag:"config" path:"file" description:"Path to the configuration file"`
+//	}
+//
+//	if len(os.Args) == 3 && os.Args[1] == "completion" {
+//		data, err := gocfg.GenerateCompletion(&Config{}, os.Args[2], "app")
+//		if err != nil {
+//			log.Fatalf("failed to generate completion: %v", err)
+//		}
+//		os.Stdout.Write(data)
+//		os.Exit(0)
+//	}
+//
+// Then, e.g. in bash: source <(app completion bash).
+func GenerateCompletion(cfg any, shell, program string, opts ...Option) ([]byte, error) {
+	return flag.Completion(cfg, shell, program, internalOptions(opts)...)
+}
@@ -0,0 +1,30 @@
+package gocfg
+
+import (
+	"github.com/dsbasko/go-cfg/internal/file"
+)
+
+// Convert is a function that reads the configuration file at the path, in any format ReadFile supports, and
+// writes it in the format, which is json, yaml, toml or env. The keys are mapped through the fields of the
+// provided cfg structure, e.g. the HTTP_PORT variable of a .env file becomes the port key of the http section
+// in YAML. Only the values the file sets are written, without the default values, the secrets are written as
+// they are and the encrypted values stay encrypted. The values of the cfg structure itself are not used.
+//
+// The keys of the file that do not map to any field are returned as dotted paths, e.g. http.prot, together
+// with the $include and profiles keys and, for env, the keys of the fields that have no environment variable,
+// none of which are converted. The xml, hcl, ini and properties files can be read but not written. The function
+// returns ErrUnsupportedFormat if either format is not supported.
+//
+// Example:
+//
+//	data, unmapped, err := gocfg.Convert(".env", "yaml", &Config{})
+//	if err != nil {
+//		log.Fatalf("failed to convert configuration: %v", err)
+//	}
+//	for _, key := range unmapped {
+//		log.Printf("key %s is not converted", key)
+//	}
+//	os.WriteFile("config.yaml", data, 0o644)
+func Convert(path, format string, cfg any, opts ...Option) ([]byte, []string, error) {
+	return file.Convert(path, format, cfg, internalOptions(opts)...)
+}
@@ -0,0 +1,48 @@
+package gocfg
+
+import (
+	"github.com/dsbasko/go-cfg/internal/crypt"
+)
+
+var (
+	// ErrNoEncryptionKey is the error of reading a configuration file with encrypted values without a key.
+	ErrNoEncryptionKey = crypt.ErrNoKey
+
+	// ErrInvalidEncryptionKey is the error of a key that is not a base64-encoded 32-byte key.
+	ErrInvalidEncryptionKey = crypt.ErrInvalidKey
+
+	// ErrInvalidEncryptedValue is the error of a malformed encrypted value.
+	ErrInvalidEncryptedValue = crypt.ErrInvalidValue
+)
+
+// GenerateKey is a function that returns a new random key for Encrypt, encoded in base64. Keep it out of the
+// repository, e.g. in the GOCFG_ENCRYPTION_KEY environment variable of the deployment.
+func GenerateKey() (string, error) {
+	return crypt.GenerateKey()
+}
+
+// Encrypt is a function that encrypts the plaintext with AES-256-GCM and the base64-encoded key from GenerateKey.
+// It returns a value in the ENC[...] form, which can be committed in any configuration file in place of the
+// plaintext. ReadFile, Load and Watch decrypt such values of string fields, so the plaintext only exists in the
+// memory of the process. The key is set with WithEncryptionKey, WithEncryptionKeyFile or WithEncryptionKeyEnv,
+// and is read from the GOCFG_ENCRYPTION_KEY environment variable by default.
+//
+// Example:
+//
+//	key, _ := gocfg.GenerateKey()
+//	value, _ := gocfg.Encrypt(key, "hunter2")
+//	fmt.Printf("password: %s\n", value) // password: ENC[...]
+//
+//	type Config struct {
+//		Password gocfg.Secret `yaml:"password"`
+//	}
+//	gocfg.MustLoad("config.yaml", &cfg, gocfg.WithEncryptionKey(key))
+func Encrypt(key, plaintext string) (string, error) {
+	return crypt.Encrypt(key, plaintext)
+}
+
+// Decrypt is a function that decrypts the value in the ENC[...] form produced by Encrypt with the base64-encoded
+// key. It returns an error if the value is malformed, the key is wrong or the value has been tampered with.
+func Decrypt(key, value string) (string, error) {
+	return crypt.Decrypt(key, value)
+}
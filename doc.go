@@ -12,23 +12,59 @@
 //
 // The library provides several functions for reading configuration data:
 //
-//	ReadEnv(cfg any) error
+//	ReadEnv(cfg any, opts ...Option) error
 //	    Reads environment variables into the provided cfg structure. Each field in the cfg structure represents an environment variable.
 //
-//	MustReadEnv(cfg any)
+//	MustReadEnv(cfg any, opts ...Option)
 //	    Similar to ReadEnv but panics if the reading process fails.
 //
-//	ReadFlag(cfg any) error
+//	ReadFlag(cfg any, opts ...Option) error
 //	    Reads command-line flags into the provided cfg structure. Each field in the cfg structure represents a command-line flag.
 //
-//	MustReadFlag(cfg any)
+//	MustReadFlag(cfg any, opts ...Option)
 //	    Similar to ReadFlag but panics if the reading process fails.
 //
-//	ReadFile(path string, cfg any) error
-//	    Reads configuration from a file into the provided cfg structure. The path parameter is the path to the configuration file. Each field in the cfg structure represents a configuration option. Supported file formats include JSON and YAML.
+//	GenerateCompletion(cfg any, shell, program string, opts ...Option) ([]byte, error)
+//	    Generates the bash, zsh or fish completion script of the flags, with their oneof values and the paths of the path fields.
 //
-//	MustReadFile(path string, cfg any)
+//	ReadFile(path string, cfg any, opts ...Option) error
+//	    Reads configuration from a file into the provided cfg structure. The path parameter is the path to the configuration file. Each field in the cfg structure represents a configuration option. Supported file formats include JSON, JSON5, JSONC, Jsonnet, YAML, TOML, HCL 1, INI, .properties, XML and .env.
+//
+//	MustReadFile(path string, cfg any, opts ...Option)
 //	    Similar to ReadFile but panics if the reading process fails.
 //
+//	Load(path string, cfg any, opts ...Option) error
+//	    Writes the default values and reads the configuration file, the environment variables and the command-line flags into the provided cfg structure, then validates it.
+//
+//	MustLoad(path string, cfg any, opts ...Option)
+//	    Similar to Load but panics if the loading process fails.
+//
+//	Watch(ctx context.Context, path string, cfg any, onChange func(Event), opts ...Option) error
+//	    Loads the configuration with Load and reloads it whenever the configuration files change.
+//
+//	WatchValue[T any](ctx context.Context, path string, onChange func(Event), opts ...Option) (*Value[T], error)
+//	    Similar to Watch but publishes every configuration into a Value, which is safe for concurrent use.
+//
+//	Explain(path string, cfg any, opts ...Option) (Provenance, error)
+//	    Loads the configuration with Load and returns the source, the location and the raw value of every field.
+//
+//	Marshal(cfg any, format string, opts ...Option) ([]byte, error)
+//	    Encodes the configuration in the yaml, json, toml or env format with the secrets redacted.
+//
+//	Convert(path, format string, cfg any, opts ...Option) ([]byte, []string, error)
+//	    Converts a configuration file to another format through the keys of the fields and returns the keys that do not map.
+//
+//	GenerateExample(cfg any, format string, opts ...Option) ([]byte, error)
+//	    Generates an example configuration file with the default values and the descriptions of the fields as comments.
+//
+//	JSONSchema(cfg any, opts ...Option) ([]byte, error)
+//	    Generates a JSON Schema of the YAML configuration files from the types and the default, description and validate tags of the fields.
+//
+//	GenerateDocs(cfg any, format, title string, opts ...Option) ([]byte, error)
+//	    Generates the Markdown or man page reference of every field: its environment variable, flags, file keys, type, default and validation.
+//
+//	Encrypt(key, plaintext string) (string, error)
+//	    Encrypts a value into the ENC[...] form, which ReadFile, Load and Watch decrypt with the key.
+//
 // Here is an example of how to use the library:
 package gocfg
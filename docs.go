@@ -0,0 +1,27 @@
+package gocfg
+
+import (
+	"github.com/dsbasko/go-cfg/internal/docs"
+)
+
+// GenerateDocs is a function that generates the reference documentation of the configuration in the provided
+// cfg structure in the format, which is markdown or man. Every field is described with its fully qualified name,
+// environment variable, long and short flags, keys in the yaml, json and toml files, type, default value,
+// validate tag and description tag, and the fields are grouped by the nested structs they belong to, with the
+// description tags of the structs as the introductions of the sections. The title is the heading of the Markdown
+// document, or the name of the man page, which is written in section 5. The values of the cfg structure itself
+// are not used, and the defaults of the secrets are written as "[REDACTED]". The function returns
+// ErrUnsupportedFormat for other formats.
+//
+// Example:
+//
+//	//go:generate go run ./cmd/docs
+//
+//	data, err := gocfg.GenerateDocs(&Config{}, "markdown", "Configuration")
+//	if err != nil {
+//		log.Fatalf("failed to generate docs: %v", err)
+//	}
+//	os.WriteFile("CONFIGURATION.md", data, 0o644)
+func GenerateDocs(cfg any, format, title string, opts ...Option) ([]byte, error) {
+	return docs.Generate(cfg, format, title, internalOptions(opts)...)
+}
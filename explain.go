@@ -0,0 +1,37 @@
+package gocfg
+
+import (
+	"github.com/dsbasko/go-cfg/internal/reload"
+)
+
+// Origin describes where the value of a field comes from: its fully qualified name, e.g. HTTP.Port, the
+// source that set it last (default, file, env or flag, empty if none did), the location of the value, e.g.
+// config.yaml:12, HTTP_PORT or --http-port, and the value as the source has it.
+type Origin = reload.Origin
+
+// Provenance lists the origins of the fields of a configuration in the order of the fields. Lookup finds the
+// origin of a single field, and WriteTo and String render the origins as a table.
+type Provenance = reload.Provenance
+
+// Explain is a function that loads the configuration into the provided cfg structure with Load and returns
+// the origins of its fields, which tell whether a value comes from the default tag, the configuration file, an
+// environment variable or a command-line flag. Every exported field is listed, and the fields no source set
+// have an empty source. The origins of a configuration watched with WatchValue are returned by its Explain
+// method. The function returns an error if the loading process fails.
+//
+// Example:
+//
+//	cfg := &Config{}
+//	provenance, err := gocfg.Explain("config.yaml", cfg)
+//	if err != nil {
+//		log.Fatalf("failed to load configuration: %v", err)
+//	}
+//	fmt.Print(provenance)
+//
+//	// FIELD      SOURCE   LOCATION       VALUE
+//	// HTTP.Host  default  default tag    localhost
+//	// HTTP.Port  env      HTTP_PORT      9090
+//	// Mode       file     config.yaml:3  prod
+func Explain(path string, cfg any, opts ...Option) (Provenance, error) {
+	return reload.Load(path, cfg, internalOptions(opts)...)
+}
@@ -0,0 +1,336 @@
+// Package gocfgcli is a command-line tool that validates and inspects the configuration files of an
+// application, so operators can check them before a rollout. Since the tool needs the configuration type
+// of the application, it is embedded into the application, e.g. as its config subcommand:
+//
+//	func main() {
+//		if len(os.Args) > 1 && os.Args[1] == "config" {
+//			os.Exit(gocfgcli.Main[Config]("app config", os.Args[2:]))
+//		}
+//		...
+//	}
+//
+// The tool provides the commands:
+//
+//	validate <file>                      Validates the file, its schema and the Validate method of the configuration.
+//	print [--format f] <file>            Prints the effective configuration: the defaults, the file and the environment.
+//	explain <file>                       Prints where the value of every field comes from.
+//	convert --to f <file>                Converts the file to another format, reporting the keys that do not map.
+//	generate example|schema|docs [--format f]
+//	                                     Generates an example configuration file, the JSON Schema or the reference
+//	                                     documentation in Markdown or as a man page.
+package gocfgcli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+
+	gocfg "github.com/dsbasko/go-cfg"
+)
+
+// ErrUsage is returned when the command or its arguments are invalid
+var ErrUsage = fmt.Errorf("invalid usage")
+
+// Tool is the command-line tool for the configuration type T.
+type Tool[T any] struct {
+	Name    string         // The name of the tool in the usage, e.g. "app config".
+	Options []gocfg.Option // The options every configuration is read with, e.g. gocfg.WithNaming.
+	Stdout  io.Writer      // Where the results are written. os.Stdout if nil.
+	Stderr  io.Writer      // Where the usage and the warnings are written. os.Stderr if nil.
+}
+
+// command is a command of the tool.
+type command[T any] struct {
+	usage       string
+	description string
+	run         func(t *Tool[T], flags *pflag.FlagSet, args []string) error
+	flags       func(flags *pflag.FlagSet)
+}
+
+// New returns the command-line tool for the configuration type T with the name used in the usage.
+// The options are passed to every read of the configuration.
+func New[T any](name string, opts ...gocfg.Option) *Tool[T] {
+	return &Tool[T]{Name: name, Options: opts}
+}
+
+// Main runs the command-line tool for the configuration type T with the arguments, e.g. os.Args[1:], and
+// returns the exit code: 0 on success, 2 for an invalid usage and 1 for any other error, which is written to
+// the standard error.
+func Main[T any](name string, args []string, opts ...gocfg.Option) int {
+	err := New[T](name, opts...).Run(args)
+	if err == nil {
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+	if errors.Is(err, ErrUsage) {
+		return 2
+	}
+	return 1
+}
+
+// Run runs the command of the arguments, e.g. validate config.yaml. It returns ErrUsage if the command or
+// its arguments are invalid, after writing the usage.
+func (t *Tool[T]) Run(args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		t.usage()
+		if len(args) == 0 {
+			return fmt.Errorf("%w: missing command", ErrUsage)
+		}
+		return nil
+	}
+
+	cmd, ok := t.commands()[args[0]]
+	if !ok {
+		t.usage()
+		return fmt.Errorf("%w: unknown command %q", ErrUsage, args[0])
+	}
+
+	flags := pflag.NewFlagSet(args[0], pflag.ContinueOnError)
+	flags.SetOutput(t.stderr())
+	flags.Usage = func() {
+		fmt.Fprintf(t.stderr(), "Usage: %s %s\n\n%s\n\n", t.Name, cmd.usage, cmd.description)
+		flags.PrintDefaults()
+	}
+	if cmd.flags != nil {
+		cmd.flags(flags)
+	}
+
+	if err := flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+
+	if err := cmd.run(t, flags, flags.Args()); err != nil {
+		if errors.Is(err, ErrUsage) {
+			flags.Usage()
+		}
+		return err
+	}
+	return nil
+}
+
+// commands is a helper method that returns the commands of the tool by their names.
+func (t *Tool[T]) commands() map[string]command[T] {
+	return map[string]command[T]{
+		"validate": {
+			usage:       "validate [--profile name] <file>",
+			description: "Validates the configuration file against the schema of the configuration, then loads it with the defaults and runs its Validate method.",
+			flags:       profileFlag,
+			run:         (*Tool[T]).validate,
+		},
+		"print": {
+			usage:       "print [--format yaml|json|toml|env] [--reveal-secrets] [--profile name] <file>",
+			description: "Prints the effective configuration: the default values, the file and the environment variables.",
+			flags: func(flags *pflag.FlagSet) {
+				profileFlag(flags)
+				flags.String("format", "yaml", "the format of the output: yaml, json, toml or env")
+				flags.Bool("reveal-secrets", false, "print the values of the secrets instead of [REDACTED]")
+			},
+			run: (*Tool[T]).print,
+		},
+		"explain": {
+			usage:       "explain [--profile name] <file>",
+			description: "Prints the source, the location and the value of every field of the effective configuration.",
+			flags:       profileFlag,
+			run:         (*Tool[T]).explain,
+		},
+		"convert": {
+			usage:       "convert --to yaml|json|toml|env <file>",
+			description: "Converts the configuration file, in any format the configuration reads, to another format, mapping the keys with the tags of the configuration and reporting the keys that do not map to any field or cannot be written.",
+			flags: func(flags *pflag.FlagSet) {
+				flags.String("to", "", "the format of the output: yaml, json, toml or env")
+			},
+			run: (*Tool[T]).convert,
+		},
+		"generate": {
+			usage:       "generate example|schema|docs [--format yaml|json|toml|env|markdown|man]",
+			description: "Generates an example configuration file with the defaults and the descriptions of the fields, the JSON Schema of the configuration files, or the reference documentation of the fields.",
+			flags: func(flags *pflag.FlagSet) {
+				flags.String("format", "yaml", "the format of the example: yaml, json, toml or env, or of the docs: markdown or man (default markdown)")
+			},
+			run: (*Tool[T]).generate,
+		},
+	}
+}
+
+// validate is the validate command.
+func (t *Tool[T]) validate(flags *pflag.FlagSet, args []string) error {
+	path, err := fileArg(args)
+	if err != nil {
+		return err
+	}
+
+	opts := append(t.options(flags), gocfg.WithSources(gocfg.SourceFile), gocfg.WithStrict(), gocfg.WithSchemaValidation())
+	if err = gocfg.Load(path, new(T), opts...); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(t.stdout(), "%s: OK\n", path)
+	return err
+}
+
+// print is the print command.
+func (t *Tool[T]) print(flags *pflag.FlagSet, args []string) error {
+	path, err := fileArg(args)
+	if err != nil {
+		return err
+	}
+
+	cfg := new(T)
+	if err = gocfg.Load(path, cfg, t.loadOptions(flags)...); err != nil {
+		return err
+	}
+
+	format, _ := flags.GetString("format")
+	opts := t.Options
+	if reveal, _ := flags.GetBool("reveal-secrets"); reveal {
+		opts = append(opts[:len(opts):len(opts)], gocfg.WithRevealSecrets())
+	}
+
+	data, err := gocfg.Marshal(cfg, format, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.stdout().Write(data)
+	return err
+}
+
+// explain is the explain command.
+func (t *Tool[T]) explain(flags *pflag.FlagSet, args []string) error {
+	path, err := fileArg(args)
+	if err != nil {
+		return err
+	}
+
+	provenance, err := gocfg.Explain(path, new(T), t.loadOptions(flags)...)
+	if err != nil {
+		return err
+	}
+
+	_, err = provenance.WriteTo(t.stdout())
+	return err
+}
+
+// convert is the convert command. The keys that are not converted are written to the standard
+// error as warnings.
+func (t *Tool[T]) convert(flags *pflag.FlagSet, args []string) error {
+	path, err := fileArg(args)
+	if err != nil {
+		return err
+	}
+
+	format, _ := flags.GetString("to")
+	if format == "" {
+		return fmt.Errorf("%w: missing --to", ErrUsage)
+	}
+
+	data, unmapped, err := gocfg.Convert(path, format, new(T), t.Options...)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range unmapped {
+		fmt.Fprintf(t.stderr(), "warning: %s: the key %q is not converted\n", path, key)
+	}
+
+	_, err = t.stdout().Write(data)
+	return err
+}
+
+// generate is the generate command.
+func (t *Tool[T]) generate(flags *pflag.FlagSet, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expected example, schema or docs", ErrUsage)
+	}
+
+	var data []byte
+	var err error
+	format, _ := flags.GetString("format")
+	switch args[0] {
+	case "example":
+		data, err = gocfg.GenerateExample(new(T), format, t.Options...)
+	case "schema":
+		data, err = gocfg.JSONSchema(new(T), t.Options...)
+	case "docs":
+		if !flags.Changed("format") {
+			format = "markdown"
+		}
+		data, err = gocfg.GenerateDocs(new(T), format, t.Name, t.Options...)
+	default:
+		return fmt.Errorf("%w: expected example, schema or docs, got %q", ErrUsage, args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = t.stdout().Write(data)
+	return err
+}
+
+// options is a helper method that returns the options of the tool together with the profile the
+// flags select, if any.
+func (t *Tool[T]) options(flags *pflag.FlagSet) []gocfg.Option {
+	opts := t.Options[:len(t.Options):len(t.Options)]
+	if profile, err := flags.GetString("profile"); err == nil && profile != "" {
+		opts = append(opts, gocfg.WithProfile(profile))
+	}
+	return opts
+}
+
+// loadOptions is a helper method that returns the options the effective configuration is loaded with:
+// the file and the environment variables, but not the flags, which are the flags of the tool.
+func (t *Tool[T]) loadOptions(flags *pflag.FlagSet) []gocfg.Option {
+	return append(t.options(flags), gocfg.WithSources(gocfg.SourceFile, gocfg.SourceEnv))
+}
+
+// usage is a helper method that writes the usage of the tool.
+func (t *Tool[T]) usage() {
+	fmt.Fprintf(t.stderr(), "Usage: %s <command> [flags] [file]\n\nCommands:\n", t.Name)
+	for _, name := range []string{"validate", "print", "explain", "convert", "generate"} {
+		cmd := t.commands()[name]
+		fmt.Fprintf(t.stderr(), "  %s\n      %s\n", cmd.usage, cmd.description)
+	}
+	fmt.Fprintf(t.stderr(), "\nRun '%s <command> --help' for the flags of a command.\n", t.Name)
+}
+
+// stdout is a helper method that returns the writer of the results.
+func (t *Tool[T]) stdout() io.Writer {
+	if t.Stdout == nil {
+		return os.Stdout
+	}
+	return t.Stdout
+}
+
+// stderr is a helper method that returns the writer of the usage.
+func (t *Tool[T]) stderr() io.Writer {
+	if t.Stderr == nil {
+		return os.Stderr
+	}
+	return t.Stderr
+}
+
+// profileFlag is a helper function that adds the flag that selects the profile.
+func profileFlag(flags *pflag.FlagSet) {
+	flags.String("profile", "", "the profile to read, e.g. prod")
+}
+
+// fileArg is a helper function that returns the path of the configuration file, the only argument of
+// the commands that read one.
+func fileArg(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("%w: expected one configuration file", ErrUsage)
+	}
+
+	if filepath.Ext(args[0]) == "" {
+		return "", fmt.Errorf("%w: the file %q has no extension to tell its format", ErrUsage, args[0])
+	}
+	return args[0], nil
+}
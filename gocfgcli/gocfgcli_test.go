@@ -0,0 +1,151 @@
+package gocfgcli
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gocfg "github.com/dsbasko/go-cfg"
+)
+
+type testConfig struct {
+	Mode string `yaml:"mode" json:"mode" env:"GOCFGCLI_MODE" default:"dev" validate:"oneof=dev prod" description:"Application mode"`
+	HTTP struct {
+		Port int `yaml:"port" json:"port" default:"8080"`
+	} `yaml:"http" json:"http"`
+	Password gocfg.Secret `yaml:"password" json:"password"`
+}
+
+func Test_Tool_Run(t *testing.T) {
+	dir := t.TempDir()
+	valid := path.Join(dir, "valid.yaml")
+	assert.NoError(t, os.WriteFile(valid, []byte("mode: prod\npassword: hunter2\n"), 0o600))
+	invalid := path.Join(dir, "invalid.yaml")
+	assert.NoError(t, os.WriteFile(invalid, []byte("mode: test\n"), 0o600))
+	unknown := path.Join(dir, "unknown.yaml")
+	assert.NoError(t, os.WriteFile(unknown, []byte("mdoe: prod\n"), 0o600))
+	dotenv := path.Join(dir, "config.env")
+	assert.NoError(t, os.WriteFile(dotenv, []byte("GOCFGCLI_MODE=prod\nHTTP_PROT=80\n"), 0o600))
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       string
+		wantStderr string
+		contains   []string
+		wantErr    error
+		wantErrMsg string
+	}{
+		{
+			name: "Validate",
+			args: []string{"validate", valid},
+			want: valid + ": OK\n",
+		},
+		{
+			name:    "Validate Schema",
+			args:    []string{"validate", invalid},
+			wantErr: gocfg.ErrSchemaViolation,
+		},
+		{
+			name:       "Validate Unknown Key",
+			args:       []string{"validate", unknown},
+			wantErrMsg: `"mdoe" (did you mean "mode"?)`,
+		},
+		{
+			name: "Print",
+			args: []string{"print", "--format", "json", valid},
+			want: "{\n  \"mode\": \"prod\",\n  \"http\": {\n    \"port\": 8080\n  },\n  \"password\": \"[REDACTED]\"\n}\n",
+		},
+		{
+			name: "Print Reveal Secrets",
+			args: []string{"print", valid, "--reveal-secrets"},
+			want: "mode: prod\nhttp:\n  port: 8080\npassword: hunter2\n",
+		},
+		{
+			name:     "Explain",
+			args:     []string{"explain", valid},
+			contains: []string{"FIELD", "Mode", valid + ":1", "HTTP.Port", "default tag", "[REDACTED]"},
+		},
+		{
+			name: "Convert",
+			args: []string{"convert", "--to", "json", valid},
+			want: "{\n  \"mode\": \"prod\",\n  \"password\": \"hunter2\"\n}\n",
+		},
+		{
+			name:       "Convert Unmapped",
+			args:       []string{"convert", "--to", "yaml", dotenv},
+			want:       "mode: prod\n",
+			wantStderr: "warning: " + dotenv + ": the key \"HTTP_PROT\" is not converted\n",
+		},
+		{
+			name:    "Convert Without Format",
+			args:    []string{"convert", valid},
+			wantErr: ErrUsage,
+		},
+		{
+			name: "Generate Example",
+			args: []string{"generate", "example"},
+			want: "# Application mode\nmode: dev\nhttp:\n  port: 8080\npassword: '[REDACTED]'\n",
+		},
+		{
+			name:     "Generate Schema",
+			args:     []string{"generate", "schema"},
+			contains: []string{`"$schema": "https://json-schema.org/draft/2020-12/schema"`, `"enum": [`},
+		},
+		{
+			name:     "Generate Docs",
+			args:     []string{"generate", "docs"},
+			contains: []string{"| `Mode` | `GOCFGCLI_MODE` | - | `yaml: mode, json: mode, toml: Mode` | `string` | `dev` | `oneof=dev prod` | Application mode |", "## HTTP"},
+		},
+		{
+			name:     "Generate Man Page",
+			args:     []string{"generate", "docs", "--format", "man"},
+			contains: []string{".SH CONFIGURATION\n", ".B HTTP.Port\n"},
+		},
+		{
+			name:    "Missing File",
+			args:    []string{"print"},
+			wantErr: ErrUsage,
+		},
+		{
+			name:    "Unknown Command",
+			args:    []string{"deploy"},
+			wantErr: ErrUsage,
+		},
+		{
+			name:    "Missing Command",
+			args:    nil,
+			wantErr: ErrUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			tool := New[testConfig]("test")
+			tool.Stdout, tool.Stderr = &stdout, &stderr
+
+			err := tool.Run(tt.args)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			if tt.wantErrMsg != "" {
+				assert.ErrorContains(t, err, tt.wantErrMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			if tt.want != "" {
+				assert.Equal(t, tt.want, stdout.String())
+			}
+			assert.Equal(t, tt.wantStderr, stderr.String())
+			for _, s := range tt.contains {
+				assert.Contains(t, stdout.String(), s)
+			}
+		})
+	}
+}
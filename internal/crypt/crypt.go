@@ -0,0 +1,198 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	rf "reflect"
+	"strings"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+// DefaultKeyEnv is the environment variable the key is read from when the options set no key.
+const DefaultKeyEnv = "GOCFG_ENCRYPTION_KEY"
+
+// The prefix and the suffix of the encrypted values.
+const (
+	prefix = "ENC["
+	suffix = "]"
+)
+
+// keySize is the size of the AES-256 keys in bytes.
+const keySize = 32
+
+var (
+	// ErrNoKey is returned when a configuration holds encrypted values but no key is set
+	ErrNoKey = fmt.Errorf("no encryption key")
+
+	// ErrInvalidKey is returned when the key is not a base64-encoded 32-byte key
+	ErrInvalidKey = fmt.Errorf("encryption key must be 32 bytes encoded in base64")
+
+	// ErrInvalidValue is returned when an encrypted value is malformed
+	ErrInvalidValue = fmt.Errorf("invalid encrypted value")
+)
+
+// GenerateKey is a function that returns a new random key encoded in base64.
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// IsEncrypted reports whether the value is an encrypted value, e.g. ENC[...].
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix) && strings.HasSuffix(value, suffix)
+}
+
+// Encrypt is a function that encrypts the plaintext with AES-256-GCM and the base64-encoded key.
+// It returns the encrypted value in the ENC[...] form, where the random nonce and the sealed
+// plaintext are encoded in base64.
+func Encrypt(key, plaintext string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefix + base64.StdEncoding.EncodeToString(sealed) + suffix, nil
+}
+
+// Decrypt is a function that decrypts the value in the ENC[...] form produced by Encrypt with
+// the base64-encoded key. It returns an error if the value is malformed, the key is wrong or
+// the value has been tampered with.
+func Decrypt(key, value string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	return decrypt(aead, value)
+}
+
+// decrypt is a helper function for Decrypt that uses the prepared cipher.
+func decrypt(aead cipher.AEAD, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", ErrInvalidValue
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(value[len(prefix) : len(value)-len(suffix)])
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrInvalidValue
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("wrong key or corrupted value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// newAEAD is a helper function that returns the AES-256-GCM cipher of the base64-encoded key.
+func newAEAD(key string) (cipher.AEAD, error) {
+	rawKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil || len(rawKey) != keySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(rawKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Key is a function that returns the key set with the options: the key itself, then the
+// content of the key file and finally the environment variable, which is DefaultKeyEnv unless
+// the options name another one. It returns an empty string if no key is set.
+func Key(opts options.Options) (string, error) {
+	if opts.EncryptionKey != "" {
+		return opts.EncryptionKey, nil
+	}
+
+	if opts.EncryptionKeyFile != "" {
+		data, err := os.ReadFile(opts.EncryptionKeyFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read key file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	keyEnv := opts.EncryptionKeyEnv
+	if keyEnv == "" {
+		keyEnv = DefaultKeyEnv
+	}
+	return os.Getenv(keyEnv), nil
+}
+
+// DecryptStruct is a function that replaces the encrypted values of the string fields of the
+// struct, including the elements of string slices and the fields of nested structs, with
+// their plaintext. The key is only looked up if the struct holds encrypted values. The
+// function returns ErrNoKey if no key is set, and the errors name the fields, never the values.
+func DecryptStruct(structPtr any, opts options.Options) error {
+	var fields []rf.Value
+	var paths []string
+	collect(rf.ValueOf(structPtr).Elem(), "", &fields, &paths)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	key, err := Key(opts)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return fmt.Errorf("%w: %s is encrypted", ErrNoKey, paths[0])
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	for i, field := range fields {
+		plaintext, errDecrypt := decrypt(aead, field.String())
+		if errDecrypt != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", paths[i], errDecrypt)
+		}
+		field.SetString(plaintext)
+	}
+
+	return nil
+}
+
+// collect is a helper function used by DecryptStruct to find the settable string values that
+// are encrypted. The prefix parameter is used to build the fully qualified names of the fields.
+func collect(valueOf rf.Value, prefix string, fields *[]rf.Value, paths *[]string) {
+	for i := 0; i < valueOf.NumField(); i++ {
+		field, value := valueOf.Type().Field(i), valueOf.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		path := prefix + field.Name
+		switch {
+		case field.Type.Kind() == rf.Struct:
+			collect(value, path+".", fields, paths)
+		case field.Type.Kind() == rf.String:
+			if IsEncrypted(value.String()) {
+				*fields, *paths = append(*fields, value), append(*paths, path)
+			}
+		case field.Type.Kind() == rf.Slice && field.Type.Elem().Kind() == rf.String:
+			for j := 0; j < value.Len(); j++ {
+				if IsEncrypted(value.Index(j).String()) {
+					*fields, *paths = append(*fields, value.Index(j)), append(*paths, fmt.Sprintf("%s[%d]", path, j))
+				}
+			}
+		}
+	}
+}
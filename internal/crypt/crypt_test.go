@@ -0,0 +1,159 @@
+package crypt
+
+import (
+	"encoding/base64"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+func Test_Encrypt(t *testing.T) {
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+	otherKey, err := GenerateKey()
+	assert.NoError(t, err)
+
+	value, err := Encrypt(key, "hunter2")
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(value))
+	assert.NotContains(t, value, "hunter2")
+
+	again, err := Encrypt(key, "hunter2")
+	assert.NoError(t, err)
+	assert.NotEqual(t, value, again, "the nonce must be random")
+
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "Decrypted", key: key, value: value, want: "hunter2"},
+		{name: "Wrong Key", key: otherKey, value: value, wantErr: "wrong key or corrupted value: cipher: message authentication failed"},
+		{name: "Tampered", key: key, value: tamper(value), wantErr: "wrong key or corrupted value: cipher: message authentication failed"},
+		{name: "Not Encrypted", key: key, value: "hunter2", wantErr: ErrInvalidValue.Error()},
+		{name: "Not Base64", key: key, value: "ENC[%%%]", wantErr: ErrInvalidValue.Error()},
+		{name: "Too Short", key: key, value: "ENC[AAAA]", wantErr: ErrInvalidValue.Error()},
+		{name: "Invalid Key", key: "c2hvcnQ=", value: value, wantErr: ErrInvalidKey.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errDecrypt := Decrypt(tt.key, tt.value)
+			if tt.wantErr != "" {
+				assert.EqualError(t, errDecrypt, tt.wantErr)
+				return
+			}
+			assert.NoError(t, errDecrypt)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// tamper is a helper function that flips a bit of the sealed plaintext of the encrypted value.
+func tamper(value string) string {
+	sealed, _ := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(value, prefix), suffix))
+	sealed[len(sealed)-1] ^= 1
+	return prefix + base64.StdEncoding.EncodeToString(sealed) + suffix
+}
+
+func Test_Key(t *testing.T) {
+	keyFile := path.Join(t.TempDir(), "key")
+	assert.NoError(t, os.WriteFile(keyFile, []byte("fileKey\n"), 0o600))
+	t.Setenv(DefaultKeyEnv, "defaultEnvKey")
+	t.Setenv("APP_KEY", "envKey")
+
+	tests := []struct {
+		name    string
+		opts    options.Options
+		want    string
+		wantErr bool
+	}{
+		{name: "Option", opts: options.Options{EncryptionKey: "optionKey", EncryptionKeyFile: keyFile}, want: "optionKey"},
+		{name: "File", opts: options.Options{EncryptionKeyFile: keyFile, EncryptionKeyEnv: "APP_KEY"}, want: "fileKey"},
+		{name: "Env", opts: options.Options{EncryptionKeyEnv: "APP_KEY"}, want: "envKey"},
+		{name: "Default Env", want: "defaultEnvKey"},
+		{name: "Missing File", opts: options.Options{EncryptionKeyFile: keyFile + ".missing"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Key(tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_DecryptStruct(t *testing.T) {
+	type InStructNested struct {
+		Password string
+	}
+	type InStruct struct {
+		Name   string
+		Token  string
+		Hosts  []string
+		Port   int
+		Nested InStructNested
+	}
+
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+	encrypt := func(plaintext string) string {
+		value, errEncrypt := Encrypt(key, plaintext)
+		assert.NoError(t, errEncrypt)
+		return value
+	}
+
+	t.Run("Decrypted", func(t *testing.T) {
+		structPtr := &InStruct{
+			Name:   "app",
+			Token:  encrypt("token"),
+			Hosts:  []string{"a", encrypt("b")},
+			Nested: InStructNested{Password: encrypt("hunter2")},
+		}
+
+		assert.NoError(t, DecryptStruct(structPtr, options.Options{EncryptionKey: key}))
+		assert.Equal(t, &InStruct{
+			Name:   "app",
+			Token:  "token",
+			Hosts:  []string{"a", "b"},
+			Nested: InStructNested{Password: "hunter2"},
+		}, structPtr)
+	})
+
+	t.Run("Nothing Encrypted Needs No Key", func(t *testing.T) {
+		t.Setenv(DefaultKeyEnv, "")
+		assert.NoError(t, DecryptStruct(&InStruct{Name: "app"}, options.Options{}))
+	})
+
+	t.Run("No Key", func(t *testing.T) {
+		t.Setenv(DefaultKeyEnv, "")
+		err := DecryptStruct(&InStruct{Hosts: []string{encrypt("b")}}, options.Options{})
+		assert.ErrorIs(t, err, ErrNoKey)
+		assert.EqualError(t, err, "no encryption key: Hosts[0] is encrypted")
+	})
+
+	t.Run("Wrong Key", func(t *testing.T) {
+		otherKey, _ := GenerateKey()
+		err := DecryptStruct(&InStruct{Nested: InStructNested{Password: encrypt("hunter2")}},
+			options.Options{EncryptionKey: otherKey})
+		assert.EqualError(t, err, "failed to decrypt Nested.Password: wrong key or corrupted value: cipher: message authentication failed")
+	})
+
+	t.Run("Invalid Key", func(t *testing.T) {
+		err := DecryptStruct(&InStruct{Token: encrypt("token")},
+			options.Options{EncryptionKey: base64.StdEncoding.EncodeToString([]byte("short"))})
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+}
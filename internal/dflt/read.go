@@ -12,6 +12,8 @@ var once sync.Once
 // Read is a function that parses default values into the provided cfg structure.
 // The cfg parameter should be a pointer to a struct where each field represents a default value.
 // The function returns an error if the parsing process fails, wrapping the original error with a message.
+// The default values are written only once per process, so reading the other sources afterwards
+// does not overwrite their values with the defaults.
 func Read(structPtr any) error {
 	if err := reflect.Validation(structPtr); err != nil {
 		return fmt.Errorf("error validating struct: %w", err)
@@ -19,17 +21,29 @@ func Read(structPtr any) error {
 
 	var result error
 	once.Do(func() {
-		parsedStruct, err := reflect.ParseTag(structPtr, "default")
-		if err != nil {
-			result = fmt.Errorf("error parsing struct: %w", err)
-		}
-
-		if err = reflect.WriteToStruct(structPtr, func(fieldName string) string {
-			return parsedStruct[fieldName].TagValue
-		}); err != nil {
-			result = fmt.Errorf("error writing to struct: %w", err)
-		}
+		result = Apply(structPtr)
 	})
 
 	return result
 }
+
+// Apply is similar to Read but writes the default values every time it is called. It is used
+// to fill a fresh structure, e.g. when the configuration is reloaded.
+func Apply(structPtr any) error {
+	if err := reflect.Validation(structPtr); err != nil {
+		return fmt.Errorf("error validating struct: %w", err)
+	}
+
+	parsedStruct, err := reflect.ParseTag(structPtr, "default")
+	if err != nil {
+		return fmt.Errorf("error parsing struct: %w", err)
+	}
+
+	if err = reflect.WriteToStruct(structPtr, func(fieldName string) string {
+		return parsedStruct[fieldName].TagValue
+	}); err != nil {
+		return fmt.Errorf("error writing to struct: %w", err)
+	}
+
+	return nil
+}
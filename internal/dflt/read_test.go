@@ -62,3 +62,17 @@ func Test_Read(t *testing.T) {
 		})
 	}
 }
+
+func Test_Apply(t *testing.T) {
+	type InStruct struct {
+		Field string `default:"fieldDefValue"`
+	}
+
+	for i := 0; i < 2; i++ {
+		structPtr := &InStruct{}
+		assert.NoError(t, Apply(structPtr))
+		assert.Equal(t, &InStruct{Field: "fieldDefValue"}, structPtr)
+	}
+
+	assert.ErrorIs(t, Apply(InStruct{}), reflect.ErrNotPointer)
+}
@@ -0,0 +1,286 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	rf "reflect"
+	"strings"
+
+	"github.com/dsbasko/go-cfg/internal/file"
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+// fileTags are the tags of the formats whose keys are documented, in the order they are listed.
+var fileTags = []string{"yaml", "json", "toml"}
+
+// group is a struct of the configuration with the fields documented in its section.
+type group struct {
+	path        string // The fully qualified name of the struct, empty for the configuration itself.
+	description string // The description tag of the struct.
+	rows        []row
+}
+
+// row describes a field of the configuration.
+type row struct {
+	path        string // The fully qualified name of the field, e.g. HTTP.Port.
+	env         string // The name of the environment variable.
+	flags       []string
+	fileKeys    string
+	typeName    string
+	defaultTag  string
+	validation  string
+	description string
+}
+
+// Generate is a function that writes the reference documentation of the configuration in the
+// structure in the format, which is markdown or man. Every field is described with its fully
+// qualified name, environment variable, command-line flags, keys in the configuration files,
+// type, default value, validation rules and description, and the fields are grouped by the
+// structs they belong to. The title is the heading of the document, or the name of the man
+// page. The defaults of the secrets are redacted. The function returns
+// file.ErrUnsupportedFormat for other formats.
+func Generate(structPtr any, format, title string, opts ...options.Option) ([]byte, error) {
+	if err := reflect.Validation(structPtr); err != nil {
+		return nil, fmt.Errorf("error validating struct: %w", err)
+	}
+
+	groups := collect(structPtr, options.New(opts...))
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		return markdown(groups, title), nil
+	case "man", "roff":
+		return man(groups, title), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", file.ErrUnsupportedFormat, format)
+	}
+}
+
+// collect is a helper function used by Generate that describes the fields of the structure,
+// grouped by the structs they belong to in the order of the fields.
+func collect(structPtr any, opts options.Options) []group {
+	typeOf := rf.TypeOf(structPtr).Elem()
+	fieldKeys := reflect.FieldKeys(structPtr, opts.Naming)
+	secrets := secret.Fields(typeOf)
+
+	var result []group
+	var walk func(typeOf rf.Type, prefix, description string, parentKeys map[string]string)
+	walk = func(typeOf rf.Type, prefix, description string, parentKeys map[string]string) {
+		current := group{path: strings.TrimSuffix(prefix, "."), description: description}
+		var nested []rf.StructField
+
+		for i := 0; i < typeOf.NumField(); i++ {
+			field := typeOf.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			if field.Type.Kind() == rf.Struct && !reflect.IsText(field.Type) {
+				nested = append(nested, field)
+				continue
+			}
+
+			path := prefix + field.Name
+			r := row{
+				path:        path,
+				env:         fieldKeys[path].Env,
+				fileKeys:    formatFileKeys(fileKeys(field, parentKeys, opts)),
+				typeName:    typeName(field.Type, secrets[path]),
+				defaultTag:  field.Tag.Get("default"),
+				validation:  field.Tag.Get("validate"),
+				description: field.Tag.Get("description"),
+			}
+			if flag := fieldKeys[path].Flag; flag != "" {
+				r.flags = append(r.flags, "--"+flag)
+			}
+			if short := field.Tag.Get("s-flag"); short != "" {
+				r.flags = append(r.flags, "-"+short)
+			}
+			if secrets[path] && r.defaultTag != "" {
+				r.defaultTag = secret.Redacted
+			}
+			current.rows = append(current.rows, r)
+		}
+
+		if len(current.rows) > 0 || current.description != "" {
+			result = append(result, current)
+		}
+		for _, field := range nested {
+			walk(field.Type, prefix+field.Name+".", field.Tag.Get("description"), fileKeys(field, parentKeys, opts))
+		}
+	}
+	walk(typeOf, "", "", nil)
+
+	return result
+}
+
+// fileKeys is a helper function used by collect that returns the dotted paths of keys of the
+// field in the configuration files by their tags, given the paths of the struct it belongs
+// to. The formats whose tag skips the field are left out.
+func fileKeys(field rf.StructField, parentKeys map[string]string, opts options.Options) map[string]string {
+	result := make(map[string]string, len(fileTags))
+	for _, tag := range fileTags {
+		parent, ok := parentKeys[tag]
+		if field.Tag.Get(tag) == "-" || !ok && parentKeys != nil {
+			continue
+		}
+
+		key := strings.Join(reflect.FileKey(field, tag, opts.Naming), ".")
+		if parent != "" {
+			key = parent + "." + key
+		}
+		result[tag] = key
+	}
+	return result
+}
+
+// formatFileKeys is a helper function used by collect that lists the keys of the field in the
+// configuration files, once if all the formats use the same key.
+func formatFileKeys(keys map[string]string) string {
+	listed := make([]string, 0, len(fileTags))
+	same := len(keys) == len(fileTags)
+	for _, tag := range fileTags {
+		key, ok := keys[tag]
+		if !ok {
+			continue
+		}
+		if key != keys[fileTags[0]] {
+			same = false
+		}
+		listed = append(listed, tag+": "+key)
+	}
+
+	if same {
+		return keys[fileTags[0]]
+	}
+	return strings.Join(listed, ", ")
+}
+
+// typeName is a helper function used by collect that returns the name of the type of a field.
+func typeName(typeOf rf.Type, isSecret bool) string {
+	name := typeOf.String()
+	if typeOf.PkgPath() == rf.TypeOf(secret.Secret("")).PkgPath() {
+		name = typeOf.Name()
+	}
+
+	if isSecret {
+		return name + " (secret)"
+	}
+	return name
+}
+
+// markdown is a helper function used by Generate to write the groups as Markdown tables.
+func markdown(groups []group, title string) []byte {
+	var buf bytes.Buffer
+	if title != "" {
+		fmt.Fprintf(&buf, "# %s\n\n", title)
+	}
+
+	for i, g := range groups {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if g.path != "" {
+			fmt.Fprintf(&buf, "## %s\n\n", g.path)
+		}
+		if g.description != "" {
+			fmt.Fprintf(&buf, "%s\n\n", g.description)
+		}
+		if len(g.rows) == 0 {
+			continue
+		}
+
+		buf.WriteString("| Field | Environment variable | Flag | File key | Type | Default | Validation | Description |\n")
+		buf.WriteString("|-------|----------------------|------|----------|------|---------|------------|-------------|\n")
+		for _, r := range g.rows {
+			flags := make([]string, len(r.flags))
+			for j, flag := range r.flags {
+				flags[j] = code(flag)
+			}
+
+			fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+				code(r.path), code(r.env), orDash(strings.Join(flags, ", ")), code(r.fileKeys),
+				code(r.typeName), code(r.defaultTag), code(r.validation), orDash(escapeCell(r.description)))
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// code is a helper function used by markdown that writes the value of a cell as code, or a
+// dash if it is empty.
+func code(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return "`" + escapeCell(value) + "`"
+}
+
+// escapeCell is a helper function used by markdown that escapes the pipes of a table cell.
+func escapeCell(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, "|", `\|`), "\n", " ")
+}
+
+// orDash is a helper function that replaces an empty value with a dash.
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// man is a helper function used by Generate to write the groups as a man page in the roff
+// format, in section 5 of the file formats.
+func man(groups []group, title string) []byte {
+	if title == "" {
+		title = "config"
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, ".TH %s 5\n", roff(strings.ToUpper(title)))
+	fmt.Fprintf(&buf, ".SH NAME\n%s \\- configuration reference\n", roff(title))
+
+	for _, g := range groups {
+		if g.path == "" {
+			buf.WriteString(".SH CONFIGURATION\n")
+		} else {
+			fmt.Fprintf(&buf, ".SS %s\n", roff(g.path))
+		}
+		if g.description != "" {
+			fmt.Fprintf(&buf, "%s\n", roff(g.description))
+		}
+
+		for _, r := range g.rows {
+			fmt.Fprintf(&buf, ".TP\n.B %s\n", roff(r.path))
+			if r.description != "" {
+				fmt.Fprintf(&buf, "%s\n", roff(r.description))
+			}
+
+			details := [][2]string{
+				{"Type", r.typeName},
+				{"Default", r.defaultTag},
+				{"Validation", r.validation},
+				{"Environment", r.env},
+				{"Flag", strings.Join(r.flags, ", ")},
+				{"File key", r.fileKeys},
+			}
+			for _, detail := range details {
+				if detail[1] != "" {
+					fmt.Fprintf(&buf, ".br\n%s: %s\n", detail[0], roff(detail[1]))
+				}
+			}
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// roff is a helper function used by man that escapes the text for roff.
+func roff(text string) string {
+	text = strings.NewReplacer(`\`, `\e`, "-", `\-`, "\n", " ").Replace(text)
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = `\&` + text
+	}
+	return text
+}
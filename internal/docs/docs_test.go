@@ -0,0 +1,93 @@
+package docs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/file"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+type docsStructTLS struct {
+	Cert string `yaml:"cert" json:"cert" toml:"cert" env:"TLS_CERT" description:"Path to the certificate"`
+}
+
+type docsStructHTTP struct {
+	Port    int           `yaml:"port" json:"port" toml:"port" env:"HTTP_PORT" flag:"http-port" s-flag:"p" default:"8080" validate:"required,min=1" description:"HTTP port"`
+	Timeout time.Duration `yaml:"timeout" json:"read_timeout"`
+	TLS     docsStructTLS `yaml:"tls" json:"tls" toml:"tls" description:"TLS settings"`
+}
+
+type docsStruct struct {
+	Mode     string         `yaml:"mode" json:"mode" toml:"mode" env:"MODE" flag:"mode" default:"dev" validate:"oneof=dev prod" description:"Mode | env"`
+	Password secret.Secret  `yaml:"password" json:"password" toml:"password" env:"PASSWORD" default:"hunter2"`
+	Started  time.Time      `yaml:"started" json:"started" toml:"started"`
+	HTTP     docsStructHTTP `yaml:"http" json:"http" toml:"http" description:"HTTP server"`
+	private  string
+}
+
+func Test_Generate(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		title   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "Markdown",
+			format: "markdown",
+			title:  "App configuration",
+			want: "# App configuration\n\n" +
+				"| Field | Environment variable | Flag | File key | Type | Default | Validation | Description |\n" +
+				"|-------|----------------------|------|----------|------|---------|------------|-------------|\n" +
+				"| `Mode` | `MODE` | `--mode` | `mode` | `string` | `dev` | `oneof=dev prod` | Mode \\| env |\n" +
+				"| `Password` | `PASSWORD` | - | `password` | `Secret (secret)` | `[REDACTED]` | - | - |\n" +
+				"| `Started` | - | - | `started` | `time.Time` | - | - | - |\n" +
+				"\n## HTTP\n\nHTTP server\n\n" +
+				"| Field | Environment variable | Flag | File key | Type | Default | Validation | Description |\n" +
+				"|-------|----------------------|------|----------|------|---------|------------|-------------|\n" +
+				"| `HTTP.Port` | `HTTP_PORT` | `--http-port`, `-p` | `http.port` | `int` | `8080` | `required,min=1` | HTTP port |\n" +
+				"| `HTTP.Timeout` | - | - | `yaml: http.timeout, json: http.read_timeout, toml: http.Timeout` | `time.Duration` | - | - | - |\n" +
+				"\n## HTTP.TLS\n\nTLS settings\n\n" +
+				"| Field | Environment variable | Flag | File key | Type | Default | Validation | Description |\n" +
+				"|-------|----------------------|------|----------|------|---------|------------|-------------|\n" +
+				"| `HTTP.TLS.Cert` | `TLS_CERT` | - | `http.tls.cert` | `string` | - | - | Path to the certificate |\n",
+		},
+		{
+			name:   "Man",
+			format: "man",
+			title:  "app",
+			want: ".TH APP 5\n.SH NAME\napp \\- configuration reference\n" +
+				".SH CONFIGURATION\n" +
+				".TP\n.B Mode\nMode | env\n.br\nType: string\n.br\nDefault: dev\n.br\nValidation: oneof=dev prod\n.br\nEnvironment: MODE\n.br\nFlag: \\-\\-mode\n.br\nFile key: mode\n" +
+				".TP\n.B Password\n.br\nType: Secret (secret)\n.br\nDefault: [REDACTED]\n.br\nEnvironment: PASSWORD\n.br\nFile key: password\n" +
+				".TP\n.B Started\n.br\nType: time.Time\n.br\nFile key: started\n" +
+				".SS HTTP\nHTTP server\n" +
+				".TP\n.B HTTP.Port\nHTTP port\n.br\nType: int\n.br\nDefault: 8080\n.br\nValidation: required,min=1\n.br\nEnvironment: HTTP_PORT\n.br\nFlag: \\-\\-http\\-port, \\-p\n.br\nFile key: http.port\n" +
+				".TP\n.B HTTP.Timeout\n.br\nType: time.Duration\n.br\nFile key: yaml: http.timeout, json: http.read_timeout, toml: http.Timeout\n" +
+				".SS HTTP.TLS\nTLS settings\n" +
+				".TP\n.B HTTP.TLS.Cert\nPath to the certificate\n.br\nType: string\n.br\nEnvironment: TLS_CERT\n.br\nFile key: http.tls.cert\n",
+		},
+		{
+			name:    "Unsupported",
+			format:  "html",
+			wantErr: file.ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(&docsStruct{}, tt.format, tt.title)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
@@ -2,18 +2,24 @@ package env
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/caarlos0/env/v10"
 
 	"github.com/dsbasko/go-cfg/internal/dflt"
+	"github.com/dsbasko/go-cfg/internal/options"
 	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/secret"
 )
 
 // Read is a function that parses environment variables into the provided cfg structure.
 // The cfg parameter should be a pointer to a struct where each field represents an
 // environment variable. The function returns an error if the parsing process fails,
 // wrapping the original error with a message.
-func Read(structPtr any) error {
+//
+// Fields with an env tag are parsed by the env library, while the fields whose names are
+// derived from canonical keys are read afterwards.
+func Read(structPtr any, opts ...options.Option) error {
 	if err := reflect.Validation(structPtr); err != nil {
 		return fmt.Errorf("error validating struct: %w", err)
 	}
@@ -22,9 +28,50 @@ func Read(structPtr any) error {
 		return fmt.Errorf("error setting default values: %w", err)
 	}
 
+	// The errors may quote the values of the variables, so the values of the secrets are redacted.
+	o := options.New(opts...)
 	if err := env.Parse(structPtr); err != nil {
-		return fmt.Errorf("failed to parse env: %w", err)
+		return secret.RedactError(fmt.Errorf("failed to parse env: %w", err), secret.EnvValues(structPtr, o.Naming))
+	}
+
+	if err := readDerived(structPtr, o); err != nil {
+		return secret.RedactError(fmt.Errorf("failed to parse env: %w", err), secret.EnvValues(structPtr, o.Naming))
+	}
+
+	if o.Track != nil {
+		track(structPtr, o)
 	}
 
 	return nil
 }
+
+// track is a helper function used by Read to report the fields set by the environment
+// variables to the Track callback of the options. Empty variables are skipped, since
+// they leave the fields unchanged.
+func track(structPtr any, opts options.Options) {
+	for field, keys := range reflect.FieldKeys(structPtr, opts.Naming) {
+		if keys.Env == "" {
+			continue
+		}
+		if value := os.Getenv(keys.Env); value != "" {
+			opts.Track(field, keys.Env, value)
+		}
+	}
+}
+
+// readDerived is a helper function used by Read to parse the environment variables of
+// the fields without an env tag, whose names are derived from their canonical keys.
+func readDerived(structPtr any, opts options.Options) error {
+	fieldKeys := reflect.FieldKeys(structPtr, opts.Naming)
+	parsedStruct, err := reflect.ParseTag(structPtr, "env")
+	if err != nil {
+		return fmt.Errorf("failed to parse tag: %w", err)
+	}
+
+	return reflect.WriteToStruct(structPtr, func(fieldName string) string {
+		if _, ok := parsedStruct[fieldName]; ok || fieldKeys[fieldName].Env == "" {
+			return ""
+		}
+		return os.Getenv(fieldKeys[fieldName].Env)
+	})
+}
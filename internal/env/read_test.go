@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dsbasko/go-cfg/internal/options"
 	"github.com/dsbasko/go-cfg/internal/reflect"
 )
 
@@ -76,3 +77,69 @@ func TestRead(t *testing.T) {
 		})
 	}
 }
+
+func TestRead_Canonical(t *testing.T) {
+	type InStructNested struct {
+		Field string `cfg:"field"`
+		Flag  bool   `cfg:"flag"`
+	}
+	type InStruct struct {
+		Field  string         `cfg:"field" env:"TAGGED_FIELD"`
+		Nested InStructNested `cfg:"nested"`
+	}
+
+	_ = os.Setenv("TAGGED_FIELD", "taggedValue")
+	_ = os.Setenv("FIELD", "ignoredValue")
+	_ = os.Setenv("NESTED_FIELD", "nestedFieldValue")
+	defer os.Clearenv()
+
+	structPtr := &InStruct{Nested: InStructNested{Flag: true}}
+	err := Read(structPtr)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &InStruct{
+		Field: "taggedValue",
+		Nested: InStructNested{
+			Field: "nestedFieldValue",
+			Flag:  true,
+		},
+	}, structPtr)
+}
+
+func TestRead_Track(t *testing.T) {
+	type InStructNested struct {
+		Field string `cfg:"field"`
+	}
+	type InStruct struct {
+		Field  string         `env:"TRACK_FIELD"`
+		Empty  string         `env:"TRACK_EMPTY"`
+		Nested InStructNested `cfg:"track.nested"`
+	}
+
+	t.Setenv("TRACK_FIELD", "fieldValue")
+	t.Setenv("TRACK_EMPTY", "")
+	t.Setenv("TRACK_NESTED_FIELD", "nestedValue")
+
+	got := map[string][2]string{}
+	err := Read(&InStruct{}, func(o *options.Options) {
+		o.Track = func(field, location, value string) {
+			got[field] = [2]string{location, value}
+		}
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][2]string{
+		"Field":        {"TRACK_FIELD", "fieldValue"},
+		"Nested.Field": {"TRACK_NESTED_FIELD", "nestedValue"},
+	}, got)
+}
+
+func TestRead_SecretError(t *testing.T) {
+	type InStruct struct {
+		Port int `env:"SECRET_PORT" secret:"true"`
+	}
+	t.Setenv("SECRET_PORT", "hunter2")
+
+	err := Read(&InStruct{})
+	assert.EqualError(t, err, `failed to parse env: env: parse error on field "Port" of type "int": strconv.ParseInt: parsing "[REDACTED]": invalid syntax`)
+}
@@ -0,0 +1,80 @@
+package file
+
+import (
+	rf "reflect"
+	"strings"
+
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// remap is a helper function used by the parsers when the struct uses canonical keys.
+// It takes a decoded document and returns a new one, where the value of every field is
+// moved from the path of keys the field has in the file to the key the decoder of the
+// format expects. The keys that do not belong to any field are dropped.
+func remap(document map[string]any, structPtr any, tag string, naming func(string) string) map[string]any {
+	return remapRecursive(document, rf.TypeOf(structPtr).Elem(), tag, naming)
+}
+
+// remapRecursive is a helper function for remap that handles a single struct type.
+func remapRecursive(document map[string]any, typeOf rf.Type, tag string, naming func(string) string) map[string]any {
+	result := map[string]any{}
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if field.Tag.Get(tag) == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == rf.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && fieldType.Kind() == rf.Struct && reflect.Segment(field, nil) == "" && field.Tag.Get(tag) == "" {
+			for key, value := range remapRecursive(document, fieldType, tag, naming) {
+				result[key] = value
+			}
+			continue
+		}
+
+		value, ok := lookupPath(document, reflect.FileKey(field, tag, naming), tag)
+		if !ok {
+			continue
+		}
+
+		if nested, isMap := value.(map[string]any); isMap && fieldType.Kind() == rf.Struct {
+			value = remapRecursive(nested, fieldType, tag, naming)
+		}
+		result[reflect.DecoderKey(field, tag)] = value
+	}
+
+	return result
+}
+
+// lookupPath is a helper function used by remap to find the value at the path of keys in
+// the document. YAML keys must match exactly, while JSON and TOML keys also match
+// case-insensitively like their decoders do.
+func lookupPath(document map[string]any, path []string, tag string) (any, bool) {
+	var current any = document
+	for _, key := range path {
+		nested, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		value, found := nested[key]
+		if !found && tag != "yaml" {
+			for name, candidate := range nested {
+				if strings.EqualFold(name, key) {
+					value, found = candidate, true
+					break
+				}
+			}
+		}
+		if !found {
+			return nil, false
+		}
+		current = value
+	}
+
+	return current, true
+}
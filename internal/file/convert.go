@@ -0,0 +1,128 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	rf "reflect"
+	"sort"
+	"strings"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// Convert is a function that reads the configuration file at the path in any supported
+// format and encodes it in the format, which is json, yaml, toml or env. The keys are
+// mapped through the fields of the struct type of structPtr, e.g. the HTTP_PORT variable
+// of a .env file becomes the port key of the http section in YAML, and only the fields the
+// file sets are written, without the default values. The values of the struct are not used,
+// the secrets are written as they are and the encrypted values stay encrypted.
+//
+// The keys of the file that do not map to any field are returned as dotted paths, together
+// with the included files, the inline sections of the profiles and, for env, the keys of the
+// fields that have no environment variable, none of which are converted. The formats xml,
+// hcl, ini and properties are read but cannot be written. The function returns
+// ErrUnsupportedFormat if either format is not supported.
+func Convert(path, format string, structPtr any, opts ...options.Option) ([]byte, []string, error) {
+	if err := reflect.Validation(structPtr); err != nil {
+		return nil, nil, fmt.Errorf("error validating struct: %w", err)
+	}
+
+	f, ok := formats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, filepath.Ext(path))
+	}
+
+	o := options.New(opts...)
+	o.RevealSecrets = true
+	if f.name == "json" && o.LenientJSON {
+		f.parse, f.nodes = parseJSON5, nodesJSON5
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if f.evaluate != nil {
+		if data, err = f.evaluate(path, data, structPtr, o); err != nil {
+			return nil, nil, fmt.Errorf("failed to evaluate %s: %w", f.name, err)
+		}
+	}
+
+	typeOf := rf.TypeOf(structPtr).Elem()
+	fresh := rf.New(typeOf).Interface()
+	if err = f.parse(bytes.NewReader(data), fresh, o); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", f.name, err)
+	}
+
+	nodes, err := f.nodes(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", f.name, err)
+	}
+
+	fields := map[string]bool{}
+	lines := map[string]int{}
+	record := func(field string, n node) {
+		fields[field] = true
+		lines[field] = n.line
+	}
+
+	var unmapped []string
+	if f.tag == "env" {
+		fieldNodesFlat(nodes, fresh, o.Naming, record)
+
+		unknown := unknownFlatKeys(nodes, fresh, o.Naming)
+		sort.Slice(unknown, func(i, j int) bool { return unknown[i].line < unknown[j].line })
+		for _, key := range unknown {
+			unmapped = append(unmapped, key.path)
+		}
+	} else {
+		fieldNodes(nodes, typeOf, f.tag, o.Naming, "", record)
+
+		tree := newKeyTree(typeOf, f.tag, o.Naming)
+		for _, n := range nodes {
+			if _, known := tree.lookup(n.key, f.tag); !known && (n.key == includeKey || n.key == profilesKey) {
+				unmapped = append(unmapped, n.key)
+			}
+		}
+		for _, key := range unknownKeys(nodes, tree, f.tag, "") {
+			if !strings.HasPrefix(key.path, profilesKey+".") {
+				unmapped = append(unmapped, key.path)
+			}
+		}
+
+		if strings.ToLower(strings.TrimPrefix(format, ".")) == "env" {
+			unmapped = append(unmapped, unwrittenENV(fresh, f.tag, o, fields, lines)...)
+		}
+	}
+
+	result, err := marshal(fresh, format, o, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, unmapped, nil
+}
+
+// unwrittenENV is a helper function used by Convert that returns the dotted paths of the keys
+// the file sets for fields without an environment variable, which the env format cannot
+// hold, in the order of their lines.
+func unwrittenENV(structPtr any, tag string, opts options.Options, fields map[string]bool, lines map[string]int) []string {
+	fieldKeys := reflect.FieldKeys(structPtr, opts.Naming)
+
+	var unwritten []leaf
+	for _, l := range leaves(structPtr, tag, opts) {
+		if fields[l.field] && fieldKeys[l.field].Env == "" {
+			unwritten = append(unwritten, l)
+		}
+	}
+	sort.SliceStable(unwritten, func(i, j int) bool { return lines[unwritten[i].field] < lines[unwritten[j].field] })
+
+	result := make([]string, len(unwritten))
+	for i, l := range unwritten {
+		result[i] = strings.Join(l.keys, ".")
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package file
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+func Test_Convert(t *testing.T) {
+	type InStructNested struct {
+		Host string `json:"host" yaml:"host" toml:"host" env:"HTTP_HOST" default:"localhost"`
+		Port int    `json:"port" yaml:"port" toml:"port" env:"HTTP_PORT"`
+	}
+	type InStruct struct {
+		Mode     string         `json:"mode" yaml:"mode" toml:"mode" env:"MODE"`
+		HTTP     InStructNested `json:"http" yaml:"http" toml:"http"`
+		Password secret.Secret  `json:"password" yaml:"password" toml:"password" env:"PASSWORD"`
+		Debug    bool           `json:"debug" yaml:"debug" toml:"debug"`
+		Started  time.Time      `json:"started" yaml:"started" toml:"started" env:"STARTED"`
+	}
+
+	tests := []struct {
+		name         string
+		file         string
+		data         string
+		format       string
+		want         string
+		wantUnmapped []string
+		wantErr      error
+	}{
+		{
+			name:   "ENV To YAML",
+			file:   "cfg.env",
+			data:   "MODE=prod\nHTTP_PORT=8080\nPASSWORD=hunter2\nHTTP_PROT=80\n",
+			format: "yaml",
+			want:   "mode: prod\nhttp:\n  port: 8080\npassword: hunter2\n",
+
+			wantUnmapped: []string{"HTTP_PROT"},
+		},
+		{
+			name:   "YAML To ENV",
+			file:   "cfg.yaml",
+			data:   "mode: prod\ndebug: true\nhttp:\n  host: example.com\n  prot: 80\nextra: 1\n",
+			format: "env",
+			want:   "HTTP_HOST=\"example.com\"\nMODE=\"prod\"\n",
+
+			wantUnmapped: []string{"http.prot", "extra", "debug"},
+		},
+		{
+			name:   "YAML Include And Profiles",
+			file:   "cfg.yaml",
+			data:   "$include: [base.yaml]\nmode: dev\nprofiles:\n  prod:\n    mode: prod\n",
+			format: "toml",
+			want:   "mode = \"dev\"\n",
+
+			wantUnmapped: []string{"$include", "profiles"},
+		},
+		{
+			name:   "JSON To TOML",
+			file:   "cfg.json",
+			data:   `{"http": {"port": 8080}, "password": "ENC[abc]"}`,
+			format: "toml",
+			want:   "password = \"ENC[abc]\"\n\n[http]\n  port = 8080\n",
+		},
+		{
+			name:   "YAML Time To JSON",
+			file:   "cfg.yaml",
+			data:   "started: 2024-01-02T03:04:05Z\n",
+			format: "json",
+			want:   "{\n  \"started\": \"2024-01-02T03:04:05Z\"\n}\n",
+		},
+		{
+			name:    "Unsupported Target",
+			file:    "cfg.json",
+			data:    `{}`,
+			format:  "xml",
+			wantErr: ErrUnsupportedFormat,
+		},
+		{
+			name:    "Unsupported Source",
+			file:    "cfg.txt",
+			data:    "mode=prod",
+			format:  "yaml",
+			wantErr: ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := path.Join(t.TempDir(), tt.file)
+			assert.NoError(t, os.WriteFile(filePath, []byte(tt.data), 0o600))
+
+			got, unmapped, err := Convert(filePath, tt.format, &InStruct{Mode: "ignored"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+			assert.Equal(t, tt.wantUnmapped, unmapped)
+		})
+	}
+}
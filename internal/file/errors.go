@@ -0,0 +1,37 @@
+package file
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrIncludeCycle is returned when configuration files include each other in a loop
+	ErrIncludeCycle = fmt.Errorf("include cycle detected")
+
+	// ErrUnknownKey is returned in the strict mode when a key does not match any field
+	ErrUnknownKey = fmt.Errorf("unknown key")
+
+	// ErrSchemaViolation is returned when the values of a file do not match the schema of the struct
+	ErrSchemaViolation = fmt.Errorf("schema violation")
+
+	// ErrUnsupportedFormat is returned when the configuration cannot be written in the format
+	ErrUnsupportedFormat = fmt.Errorf("unsupported format")
+)
+
+// includeError is returned when reading an included file fails. It keeps the chain of
+// files that led to the failing one, so the error message shows where the include came from.
+type includeError struct {
+	chain []string
+	err   error
+}
+
+// Error returns the include chain followed by the original error message.
+func (e *includeError) Error() string {
+	return fmt.Sprintf("include chain %s: %v", strings.Join(e.chain, " -> "), e.err)
+}
+
+// Unwrap returns the original error.
+func (e *includeError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,182 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	rf "reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+
+	"github.com/dsbasko/go-cfg/internal/dflt"
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// bareKey matches the TOML keys that can be written without quotes.
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Example is a function that generates an example configuration file for the type of the
+// cfg structure in the format, which is json, yaml, toml or env. Every field is written
+// with its value from the default tag, and the description tags of the fields are written
+// as comments, except in json, which has none. The keys are the same Marshal writes, and the
+// environment variables are written in the order of the fields. The values of the cfg
+// structure itself are not used.
+func Example(structPtr any, format string, opts ...options.Option) ([]byte, error) {
+	if err := reflect.Validation(structPtr); err != nil {
+		return nil, fmt.Errorf("error validating struct: %w", err)
+	}
+
+	defaults := rf.New(rf.TypeOf(structPtr).Elem()).Interface()
+	if err := dflt.Apply(defaults); err != nil {
+		return nil, fmt.Errorf("failed to apply default values: %w", err)
+	}
+
+	o := options.New(opts...)
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "json":
+		return marshalJSON(newTree(leaves(defaults, "json", o)))
+	case "yaml", "yml":
+		return marshalYAML(newTree(leaves(defaults, "yaml", o)), true)
+	case "toml":
+		return exampleTOML(newTree(leaves(defaults, "toml", o)))
+	case "env":
+		return exampleENV(envVariables(defaults, leaves(defaults, "env", o), o))
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// comment is a helper function that returns the description as the lines of a comment.
+func comment(description string) string {
+	if description == "" {
+		return ""
+	}
+
+	lines := strings.Split(description, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("# "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// exampleTOML is a helper function used by Example to write the tree as TOML with the
+// descriptions of the keys as comments. Unlike marshalTOML, it keeps the order of the keys,
+// except that the plain values of a table come before its subtables, as TOML requires.
+func exampleTOML(t *tree) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeTOMLTable(&buf, t, nil); err != nil {
+		return nil, fmt.Errorf("failed to marshal toml: %w", err)
+	}
+	return bytes.TrimLeft(buf.Bytes(), "\n"), nil
+}
+
+// writeTOMLTable is a helper function used by exampleTOML to write the table of the tree
+// under the path of keys. Maps are written as subtables with sorted keys.
+func writeTOMLTable(buf *bytes.Buffer, t *tree, path []string) error {
+	var tables []string
+	for _, key := range t.keys {
+		value := t.values[key]
+		if _, ok := value.(*tree); ok || rf.ValueOf(value).Kind() == rf.Map {
+			tables = append(tables, key)
+			continue
+		}
+
+		if err := writeTOMLValue(buf, key, value, t.descriptions[key]); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range tables {
+		tablePath := append(path[:len(path):len(path)], key)
+
+		buf.WriteByte('\n')
+		if c := comment(t.descriptions[key]); c != "" {
+			buf.WriteString(c + "\n")
+		}
+		buf.WriteString("[" + tomlPath(tablePath) + "]\n")
+
+		child, ok := t.values[key].(*tree)
+		if !ok {
+			child = mapTree(t.values[key])
+		}
+		if err := writeTOMLTable(buf, child, tablePath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeTOMLValue is a helper function used by writeTOMLTable to write a key with its plain
+// value and description.
+func writeTOMLValue(buf *bytes.Buffer, key string, value any, description string) error {
+	if c := comment(description); c != "" {
+		buf.WriteString(c + "\n")
+	}
+	if err := toml.NewEncoder(buf).Encode(map[string]any{key: value}); err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	return nil
+}
+
+// mapTree is a helper function used by writeTOMLTable that returns the map as a tree with
+// sorted keys.
+func mapTree(value any) *tree {
+	valueOf := rf.ValueOf(value)
+
+	keys := make([]string, 0, valueOf.Len())
+	values := make(map[string]any, valueOf.Len())
+	for _, key := range valueOf.MapKeys() {
+		name := fmt.Sprint(key.Interface())
+		keys = append(keys, name)
+		values[name] = valueOf.MapIndex(key).Interface()
+	}
+	sort.Strings(keys)
+
+	result := newSubtree()
+	for _, key := range keys {
+		result.set(key, values[key], "")
+	}
+	return result
+}
+
+// tomlPath is a helper function that joins the keys of a table header, quoting the keys
+// that are not bare.
+func tomlPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		if bareKey.MatchString(key) {
+			keys[i] = key
+		} else {
+			keys[i] = strconv.Quote(key)
+		}
+	}
+	return strings.Join(keys, ".")
+}
+
+// exampleENV is a helper function used by Example to write the environment variables in
+// their order with the descriptions of the fields as comments.
+func exampleENV(variables []envVariable) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, variable := range variables {
+		line, err := godotenv.Marshal(map[string]string{variable.name: variable.value})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal env: %w", err)
+		}
+
+		c := comment(variable.description)
+		if c != "" {
+			if i > 0 {
+				buf.WriteByte('\n')
+			}
+			buf.WriteString(c + "\n")
+		}
+		buf.WriteString(line + "\n")
+	}
+	return buf.Bytes(), nil
+}
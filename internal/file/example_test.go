@@ -0,0 +1,125 @@
+package file
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type exampleStructNested struct {
+	Host string `json:"host" yaml:"host" toml:"host" env:"HTTP_HOST" default:"localhost" description:"HTTP host"`
+	Port int    `json:"port" yaml:"port" toml:"port" env:"HTTP_PORT" default:"8080" description:"HTTP port"`
+}
+
+type exampleStruct struct {
+	Mode  string              `json:"mode" yaml:"mode" toml:"mode" env:"MODE" default:"dev" description:"Application mode"`
+	Debug bool                `json:"debug" yaml:"debug" toml:"debug" env:"DEBUG"`
+	HTTP  exampleStructNested `json:"http" yaml:"http" toml:"http" description:"HTTP server"`
+}
+
+func Test_Example(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "JSON",
+			format: "json",
+			want: `{
+  "mode": "dev",
+  "debug": false,
+  "http": {
+    "host": "localhost",
+    "port": 8080
+  }
+}
+`,
+		},
+		{
+			name:   "YAML",
+			format: "yaml",
+			want: `# Application mode
+mode: dev
+debug: false
+# HTTP server
+http:
+  # HTTP host
+  host: localhost
+  # HTTP port
+  port: 8080
+`,
+		},
+		{
+			name:   "TOML",
+			format: "toml",
+			want: `# Application mode
+mode = "dev"
+debug = false
+
+# HTTP server
+[http]
+# HTTP host
+host = "localhost"
+# HTTP port
+port = 8080
+`,
+		},
+		{
+			name:   "ENV",
+			format: ".env",
+			want: `# Application mode
+MODE="dev"
+DEBUG="false"
+
+# HTTP host
+HTTP_HOST="localhost"
+
+# HTTP port
+HTTP_PORT=8080
+`,
+		},
+		{
+			name:    "Unsupported",
+			format:  "xml",
+			wantErr: ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Example(&exampleStruct{Mode: "prod"}, tt.format)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
+
+func Test_Example_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, format := range []string{"json", "yaml", "toml", "env"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := Example(&exampleStruct{}, format)
+			assert.NoError(t, err)
+
+			filePath := path.Join(dir, "config."+format)
+			assert.NoError(t, os.WriteFile(filePath, data, 0o600))
+
+			got := &exampleStruct{}
+			assert.NoError(t, Read(filePath, got))
+			assert.Equal(t, &exampleStruct{
+				Mode: "dev",
+				HTTP: exampleStructNested{Host: "localhost", Port: 8080},
+			}, got)
+		})
+	}
+}
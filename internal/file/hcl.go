@@ -0,0 +1,164 @@
+package file
+
+import (
+	"encoding/json"
+	"io"
+	rf "reflect"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// parseHCL is a helper function used by Read to parse the HCL content of the file.
+// It takes an io.Reader and a pointer to a struct where each field represents a
+// configuration option. Nested blocks are decoded into nested structs. The content is
+// decoded with HCL 1, which has no expressions, functions, for loops or typed blocks of
+// HCL 2, so files using them fail to parse. The function returns an error if the parsing
+// process fails.
+func parseHCL(r io.Reader, structPtr any, opts options.Options) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if !reflect.HasCanonicalKeys(rf.TypeOf(structPtr), opts.Naming) {
+		return hcl.Unmarshal(data, structPtr)
+	}
+
+	var document map[string]any
+	if err = hcl.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	// The HCL decoder accepts JSON as well, which keeps the remapped document simple.
+	data, err = json.Marshal(remap(mergeBlocks(document).(map[string]any), structPtr, "hcl", opts.Naming))
+	if err != nil {
+		return err
+	}
+
+	return hcl.Unmarshal(data, structPtr)
+}
+
+// mergeBlocks is a helper function used by parseHCL to turn the blocks of the decoded
+// document, which the decoder returns as lists of objects, into plain nested objects.
+func mergeBlocks(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			typed[key] = mergeBlocks(nested)
+		}
+		return typed
+	case []map[string]any:
+		result := map[string]any{}
+		for _, block := range typed {
+			for key, nested := range mergeBlocks(block).(map[string]any) {
+				result[key] = nested
+			}
+		}
+		return result
+	}
+
+	return value
+}
+
+// includesHCL is a helper function used by Read to list the files included by the HCL
+// content. Since the dollar sign is not allowed in identifiers, the key must be quoted in
+// the file. It returns an error if the content is not a valid HCL document.
+func includesHCL(data []byte) ([]string, error) {
+	var document map[string]any
+	if err := hcl.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return includeList(document)
+}
+
+// nodesHCL is a helper function used by checkStrict to list the keys of the HCL content.
+// The labels of a block are nested keys of the block name.
+func nodesHCL(data []byte) ([]node, error) {
+	file, err := hcl.ParseBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	list, ok := file.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, nil
+	}
+
+	return walkHCL(list), nil
+}
+
+// walkHCL is a helper function used by nodesHCL to list the keys of the object list.
+func walkHCL(list *ast.ObjectList) []node {
+	var result []node
+	for _, item := range list.Items {
+		path := make([]string, len(item.Keys))
+		for i, key := range item.Keys {
+			path[i], _ = key.Token.Value().(string)
+		}
+
+		var children []node
+		if object, ok := item.Val.(*ast.ObjectType); ok {
+			children = walkHCL(object.List)
+		}
+		result = mergeNode(result, path, node{line: item.Pos().Line, value: rawHCL(item.Val), children: children})
+	}
+
+	return result
+}
+
+// mergeNode is a helper function used by walkHCL to add the key path with the line, the
+// value and the children of the item to the nodes, merging it with the keys defined before.
+func mergeNode(nodes []node, path []string, item node) []node {
+	if len(path) == 0 {
+		for _, child := range item.children {
+			nodes = mergeNode(nodes, []string{child.key}, child)
+		}
+		return nodes
+	}
+
+	for i := range nodes {
+		if nodes[i].key == path[0] {
+			nodes[i].children = mergeNode(nodes[i].children, path[1:], item)
+			if len(path) == 1 && item.value != "" {
+				nodes[i].value = item.value
+			}
+			return nodes
+		}
+	}
+
+	n := node{key: path[0], line: item.line, children: mergeNode(nil, path[1:], item)}
+	if len(path) == 1 {
+		n.value = item.value
+	}
+	return append(nodes, n)
+}
+
+// rawHCL is a helper function used by walkHCL that returns the raw value of the item: the
+// text of a literal without the quotes of a string, or the elements of a list in brackets.
+// It returns an empty string for objects.
+func rawHCL(value ast.Node) string {
+	switch typed := value.(type) {
+	case *ast.LiteralType:
+		if text, ok := typed.Token.Value().(string); ok {
+			return text
+		}
+		return typed.Token.Text
+	case *ast.ListType:
+		elements := make([]string, len(typed.List))
+		for i, element := range typed.List {
+			if literal, ok := element.(*ast.LiteralType); ok {
+				elements[i] = literal.Token.Text
+			} else {
+				elements[i] = rawHCL(element)
+			}
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,286 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// entry represents a key of a flat configuration file, such as INI or .properties,
+// together with its value and the line it is defined on.
+type entry struct {
+	key   string // The dotted path of the key, e.g. http.port for the port key of the [http] section.
+	value string // The raw value of the key.
+	line  int    // The line of the key, starting at 1.
+}
+
+// parseINI is a helper function used by Read to parse the INI content of the file.
+// It takes an io.Reader and a pointer to a struct where each field represents a
+// configuration option. Sections map to nested structs, and the values are converted
+// the same way as for .env files. The function returns an error if the parsing process fails.
+func parseINI(r io.Reader, structPtr any, opts options.Options) error {
+	entries, err := entriesINI(r)
+	if err != nil {
+		return err
+	}
+
+	return writeEntries(entries, structPtr, "ini", opts)
+}
+
+// parseProperties is a helper function used by Read to parse the content of the Java
+// .properties file. It takes an io.Reader and a pointer to a struct where each field
+// represents a configuration option. Dotted keys map to nested structs, and the values
+// are converted the same way as for .env files. The function returns an error if the
+// parsing process fails.
+func parseProperties(r io.Reader, structPtr any, opts options.Options) error {
+	entries, err := entriesProperties(r)
+	if err != nil {
+		return err
+	}
+
+	return writeEntries(entries, structPtr, "properties", opts)
+}
+
+// writeEntries is a helper function used by parseINI and parseProperties to write the
+// values of the entries to the fields. The keys are matched case-insensitively against
+// the paths of keys built from the tag of the format.
+func writeEntries(entries []entry, structPtr any, tag string, opts options.Options) error {
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[strings.ToLower(e.key)] = e.value
+	}
+
+	fileKeys := reflect.FileKeys(structPtr, tag, opts.Naming)
+	return reflect.WriteToStruct(structPtr, func(fieldName string) string {
+		if _, ok := fileKeys[fieldName]; !ok {
+			return ""
+		}
+		return values[strings.ToLower(fileKeys[fieldName])]
+	})
+}
+
+// entriesINI is a helper function that reads the keys of the INI content. Lines starting
+// with a semicolon or a hash are comments, the values may be quoted, and the keys of a
+// section are prefixed with its name. It returns an error for a line that is neither a
+// section, a comment nor a key.
+func entriesINI(r io.Reader) ([]entry, error) {
+	var result []entry
+	var section string
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, ";") || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section %q", line, text)
+			}
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			continue
+		}
+
+		separator := strings.IndexAny(text, "=:")
+		if separator <= 0 {
+			return nil, fmt.Errorf("line %d: expected key = value, got %q", line, text)
+		}
+
+		key := strings.TrimSpace(text[:separator])
+		if section != "" {
+			key = section + "." + key
+		}
+		result = append(result, entry{key: key, value: unquote(strings.TrimSpace(text[separator+1:])), line: line})
+	}
+
+	return result, scanner.Err()
+}
+
+// entriesProperties is a helper function that reads the keys of the .properties content.
+// Lines starting with a hash or an exclamation mark are comments, keys are separated from
+// the values by an equal sign, a colon or a whitespace, a backslash at the end of a line
+// continues the value on the next line, and the usual escape sequences are supported.
+func entriesProperties(r io.Reader) ([]entry, error) {
+	var result []entry
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimLeft(scanner.Text(), " \t\f")
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "!") {
+			continue
+		}
+
+		start := line
+		for continues(text) && scanner.Scan() {
+			line++
+			text = text[:len(text)-1] + strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+
+		key, value := splitProperty(text)
+		result = append(result, entry{key: unescapeProperty(key), value: unescapeProperty(value), line: start})
+	}
+
+	return result, scanner.Err()
+}
+
+// continues is a helper function used by entriesProperties that reports whether the line
+// continues on the next one: it ends with an odd number of backslashes, since a pair of
+// backslashes is an escaped backslash.
+func continues(text string) bool {
+	count := len(text) - len(strings.TrimRight(text, `\`))
+	return count%2 == 1
+}
+
+// splitProperty is a helper function used by entriesProperties to split the line into
+// the key and the value at the first unescaped separator.
+func splitProperty(text string) (key, value string) {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '=', ':', ' ', '\t', '\f':
+			value = strings.TrimLeft(text[i:], " \t\f")
+			if value != "" && (value[0] == '=' || value[0] == ':') {
+				value = strings.TrimLeft(value[1:], " \t\f")
+			}
+			return text[:i], value
+		}
+	}
+
+	return text, ""
+}
+
+// unescapeProperty is a helper function used by entriesProperties to replace the escape
+// sequences of the .properties format with the characters they stand for.
+func unescapeProperty(text string) string {
+	if !strings.Contains(text, `\`) {
+		return text
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' || i+1 == len(text) {
+			buf.WriteByte(text[i])
+			continue
+		}
+
+		i++
+		switch text[i] {
+		case 't':
+			buf.WriteByte('\t')
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 'f':
+			buf.WriteByte('\f')
+		case 'u':
+			if i+4 < len(text) {
+				if code, err := strconv.ParseUint(text[i+1:i+5], 16, 32); err == nil {
+					buf.WriteRune(rune(code))
+					i += 4
+					continue
+				}
+			}
+			buf.WriteByte(text[i])
+		default:
+			buf.WriteByte(text[i])
+		}
+	}
+
+	return buf.String()
+}
+
+// unquote is a helper function used by entriesINI to remove the double or single quotes
+// around the value.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// includesEntries is a helper function that lists the files included by a flat
+// configuration file, which are separated by commas in the value of the top-level key.
+func includesEntries(entries []entry) []string {
+	var result []string
+	for _, e := range entries {
+		if e.key != includeKey {
+			continue
+		}
+		for _, include := range strings.Split(e.value, ",") {
+			if include = strings.TrimSpace(include); include != "" {
+				result = append(result, include)
+			}
+		}
+	}
+	return result
+}
+
+// includesINI is a helper function used by Read to list the files included by the INI
+// content. It returns an error if the content is not a valid INI document.
+func includesINI(data []byte) ([]string, error) {
+	entries, err := entriesINI(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return includesEntries(entries), nil
+}
+
+// includesProperties is a helper function used by Read to list the files included by the
+// .properties content.
+func includesProperties(data []byte) ([]string, error) {
+	entries, err := entriesProperties(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return includesEntries(entries), nil
+}
+
+// nodesEntries is a helper function that turns the entries of a flat configuration file
+// into nested keys, where the dots of the keys separate the levels.
+func nodesEntries(entries []entry) []node {
+	lines := make(map[string]int, len(entries))
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[e.key] = e.value
+		parts := strings.Split(e.key, ".")
+		for i := range parts {
+			if _, ok := lines[strings.Join(parts[:i+1], ".")]; !ok {
+				lines[strings.Join(parts[:i+1], ".")] = e.line
+			}
+		}
+	}
+
+	var result []node
+	for _, e := range entries {
+		result = insertNode(result, strings.Split(e.key, "."), lines, values, "")
+	}
+	return result
+}
+
+// nodesINI is a helper function used by checkStrict to list the keys of the INI content.
+func nodesINI(data []byte) ([]node, error) {
+	entries, err := entriesINI(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return nodesEntries(entries), nil
+}
+
+// nodesProperties is a helper function used by checkStrict to list the keys of the
+// .properties content.
+func nodesProperties(data []byte) ([]node, error) {
+	entries, err := entriesProperties(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return nodesEntries(entries), nil
+}
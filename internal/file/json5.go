@@ -0,0 +1,338 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+// parseJSON5 is a helper function used by Read to parse the JSON5 or JSONC content of
+// the file. The content is converted to JSON first, keeping every line in place, so the
+// struct is decoded the same way as for JSON files and the lines in the errors match
+// the original content. The function returns an error if the parsing process fails.
+func parseJSON5(r io.Reader, structPtr any, opts options.Options) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if data, err = standardizeJSON(data); err != nil {
+		return err
+	}
+
+	if err = parseJSON(bytes.NewReader(data), structPtr, opts); err != nil {
+		var errSyntax *json.SyntaxError
+		if errors.As(err, &errSyntax) {
+			return fmt.Errorf("line %d: %w", lineAt(data, errSyntax.Offset), err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// includesJSON5 is a helper function used by Read to list the files included by the
+// JSON5 or JSONC content.
+func includesJSON5(data []byte) ([]string, error) {
+	data, err := standardizeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return includesJSON(data)
+}
+
+// nodesJSON5 is a helper function used by checkStrict to list the keys of the JSON5 or
+// JSONC content.
+func nodesJSON5(data []byte) ([]node, error) {
+	data, err := standardizeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return nodesJSON(data)
+}
+
+// standardizeJSON is a helper function that converts JSON5 or JSONC content to JSON.
+// Line and block comments and trailing commas are replaced with spaces, unquoted keys
+// are quoted, single-quoted strings become double-quoted, the escapes and the line
+// continuations of the strings are converted, and hexadecimal numbers, numbers with a
+// plus sign or a leading or trailing decimal point are written as JSON numbers. Line
+// breaks are kept, so every value stays on the line it is defined on. Since JSON cannot
+// represent them, it returns an error for Infinity and NaN.
+func standardizeJSON(data []byte) ([]byte, error) {
+	result := make([]byte, 0, len(data)+len(data)/8)
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"' || c == '\'':
+			end, quoted, err := quoteString(data, i)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, quoted...)
+			i = end
+
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for ; i < len(data) && data[i] != '\n'; i++ {
+				result = append(result, ' ')
+			}
+			i--
+
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", lineAt(data, int64(i)))
+			}
+			for _, b := range data[i : i+end+4] {
+				if b == '\n' {
+					result = append(result, '\n')
+				} else {
+					result = append(result, ' ')
+				}
+			}
+			i += end + 3
+
+		case c == ',' && closesContainer(data, i+1):
+			result = append(result, ' ')
+
+		case isNumberStart(data, i):
+			end, number, err := convertNumber(data, i)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, number...)
+			i = end - 1
+
+		case isIdentStart(c):
+			end := i
+			for end < len(data) && isIdentPart(data[end]) {
+				end++
+			}
+			switch {
+			case isKey(data, end):
+				result = append(result, '"')
+				result = append(result, data[i:end]...)
+				result = append(result, '"')
+			case isNonFinite(data[i:end]):
+				return nil, fmt.Errorf("line %d: %s is not supported, since JSON cannot represent it", lineAt(data, int64(i)), data[i:end])
+			default:
+				result = append(result, data[i:end]...)
+			}
+			i = end - 1
+
+		default:
+			result = append(result, c)
+		}
+	}
+
+	return result, nil
+}
+
+// quoteString is a helper function used by standardizeJSON to convert the string that
+// starts at the offset to a double-quoted JSON string. The line breaks of the line
+// continuations are written after the closing quote, so the lines after the string stay
+// in place. It returns the offset of the closing quote and the converted string.
+func quoteString(data []byte, start int) (int, []byte, error) {
+	quote := data[start]
+	result := []byte{'"'}
+	var continuations int
+	for i := start + 1; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == '\\' && i+1 < len(data):
+			i++
+			switch next := data[i]; {
+			case next == '\n':
+				continuations++
+			case next == '\r':
+				continuations++
+				if i+1 < len(data) && data[i+1] == '\n' {
+					i++
+				}
+			case next == 'x' && i+2 < len(data) && isHex(data[i+1]) && isHex(data[i+2]):
+				result = append(result, `\u00`...)
+				result = append(result, data[i+1], data[i+2])
+				i += 2
+			case next == 'v':
+				result = append(result, `\u000b`...)
+			case next == '0' && (i+1 == len(data) || data[i+1] < '0' || data[i+1] > '9'):
+				result = append(result, `\u0000`...)
+			case bytes.IndexByte([]byte(`"\\/bfnrtu`), next) >= 0:
+				result = append(result, c, next)
+			default:
+				result = append(result, next)
+			}
+		case c == quote:
+			result = append(result, '"')
+			for ; continuations > 0; continuations-- {
+				result = append(result, '\n')
+			}
+			return i, result, nil
+		case c == '"':
+			result = append(result, '\\', '"')
+		case c == '\n':
+			return 0, nil, fmt.Errorf("line %d: unterminated string", lineAt(data, int64(start)))
+		default:
+			result = append(result, c)
+		}
+	}
+
+	return 0, nil, fmt.Errorf("line %d: unterminated string", lineAt(data, int64(start)))
+}
+
+// isNumberStart is a helper function used by standardizeJSON to check whether a number
+// starts at the offset: a digit, a decimal point followed by a digit, or a sign.
+func isNumberStart(data []byte, offset int) bool {
+	c := data[offset]
+	if c >= '0' && c <= '9' {
+		return true
+	}
+
+	next := byte(0)
+	if offset+1 < len(data) {
+		next = data[offset+1]
+	}
+	switch c {
+	case '.':
+		return next >= '0' && next <= '9'
+	case '+', '-':
+		return next >= '0' && next <= '9' || next == '.' || next == 'I' || next == 'N'
+	}
+	return false
+}
+
+// convertNumber is a helper function used by standardizeJSON to convert the JSON5 number
+// that starts at the offset to a JSON number: the plus sign is dropped, hexadecimal
+// numbers are written in decimal, and a missing integer or fractional part is completed.
+// It returns the offset after the number and the converted number.
+func convertNumber(data []byte, start int) (int, []byte, error) {
+	i := start
+	var result []byte
+	if data[i] == '+' || data[i] == '-' {
+		if data[i] == '-' {
+			result = append(result, '-')
+		}
+		i++
+	}
+
+	if i+1 < len(data) && data[i] == '0' && (data[i+1] == 'x' || data[i+1] == 'X') {
+		end := i + 2
+		for end < len(data) && isHex(data[end]) {
+			end++
+		}
+		value, err := strconv.ParseUint(string(data[i+2:end]), 16, 64)
+		if err != nil {
+			return 0, nil, fmt.Errorf("line %d: invalid hexadecimal number %q", lineAt(data, int64(start)), data[start:end])
+		}
+		return end, strconv.AppendUint(result, value, 10), nil
+	}
+
+	if isIdentStart(data[i]) {
+		end := i
+		for end < len(data) && isIdentPart(data[end]) {
+			end++
+		}
+		if isNonFinite(data[i:end]) {
+			return 0, nil, fmt.Errorf("line %d: %s is not supported, since JSON cannot represent it", lineAt(data, int64(start)), data[start:end])
+		}
+		return end, append(result, data[i:end]...), nil
+	}
+
+	digits := func() []byte {
+		from := i
+		for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+			i++
+		}
+		return data[from:i]
+	}
+
+	integer := digits()
+	if len(integer) == 0 {
+		integer = []byte{'0'}
+	}
+	result = append(result, integer...)
+
+	if i < len(data) && data[i] == '.' {
+		i++
+		if fraction := digits(); len(fraction) > 0 {
+			result = append(result, '.')
+			result = append(result, fraction...)
+		}
+	}
+
+	if i < len(data) && (data[i] == 'e' || data[i] == 'E') {
+		result = append(result, data[i])
+		i++
+		if i < len(data) && (data[i] == '+' || data[i] == '-') {
+			result = append(result, data[i])
+			i++
+		}
+		result = append(result, digits()...)
+	}
+
+	return i, result, nil
+}
+
+// isNonFinite is a helper function that reports whether the identifier is one of the
+// JSON5 numbers JSON cannot represent.
+func isNonFinite(ident []byte) bool {
+	return string(ident) == "Infinity" || string(ident) == "NaN"
+}
+
+// isHex is a helper function that reports whether the character is a hexadecimal digit.
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// closesContainer is a helper function used by standardizeJSON to check whether the next
+// significant character after the offset closes an object or an array, which makes the
+// comma before it a trailing one.
+func closesContainer(data []byte, offset int) bool {
+	for i := offset; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return false
+			}
+			i += end + 3
+		default:
+			return c == '}' || c == ']'
+		}
+	}
+
+	return false
+}
+
+// isKey is a helper function used by standardizeJSON to check whether the identifier
+// that ends at the offset is followed by a colon, which makes it an unquoted key.
+func isKey(data []byte, offset int) bool {
+	for i := offset; i < len(data); i++ {
+		switch data[i] {
+		case ' ', '\t', '\r', '\n':
+		default:
+			return data[i] == ':'
+		}
+	}
+	return false
+}
+
+// isIdentStart is a helper function that reports whether the character may start an
+// unquoted key.
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isIdentPart is a helper function that reports whether the character may be a part of
+// an unquoted key.
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,141 @@
+package file
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+func Test_standardizeJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Plain JSON",
+			data: `{"field": "value", "list": [1, 2]}`,
+			want: `{"field": "value", "list": [1, 2]}`,
+		},
+		{
+			name: "Comments",
+			data: "{\n  // line\n  \"field\": /* block */ 1\n}",
+			want: "{\n         \n  \"field\":             1\n}",
+		},
+		{
+			name: "Multiline Comment",
+			data: "/* a\nb */{}",
+			want: "    \n    {}",
+		},
+		{
+			name: "Trailing Commas",
+			data: "{\"list\": [1, 2,], \"field\": 1, // comment\n}",
+			want: "{\"list\": [1, 2 ], \"field\": 1            \n}",
+		},
+		{
+			name: "Unquoted Keys",
+			data: `{field: true, $nested_1 : null}`,
+			want: `{"field": true, "$nested_1" : null}`,
+		},
+		{
+			name: "Single Quotes",
+			data: `{'field': 'it\'s "quoted"'}`,
+			want: `{"field": "it's \"quoted\""}`,
+		},
+		{
+			name: "Comment In String",
+			data: `{"url": "http://localhost"}`,
+			want: `{"url": "http://localhost"}`,
+		},
+		{
+			name: "Numbers",
+			data: `{hex: 0x1F, negative: -0XfF, plus: +1, leading: .5, trailing: 5., exponent: -2.E+3, key1: 1}`,
+			want: `{"hex": 31, "negative": -255, "plus": 1, "leading": 0.5, "trailing": 5, "exponent": -2E+3, "key1": 1}`,
+		},
+		{
+			name: "String Escapes",
+			data: `{'field': 'a\x41\v\0\q\n'}`,
+			want: `{"field": "a\u0041\u000b\u0000q\n"}`,
+		},
+		{
+			name: "Line Continuation",
+			data: "{'field': 'first \\\nsecond',\n'next': 1}",
+			want: "{\"field\": \"first second\"\n,\n\"next\": 1}",
+		},
+		{
+			name:    "Infinity",
+			data:    "{\n'field': -Infinity}",
+			wantErr: "line 2: -Infinity is not supported, since JSON cannot represent it",
+		},
+		{
+			name:    "NaN",
+			data:    "{field: NaN}",
+			wantErr: "line 1: NaN is not supported, since JSON cannot represent it",
+		},
+		{
+			name:    "Unterminated Comment",
+			data:    "{\n/* comment",
+			wantErr: "line 2: unterminated comment",
+		},
+		{
+			name:    "Unterminated String",
+			data:    "{\n\n'field: 1}",
+			wantErr: "line 3: unterminated string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := standardizeJSON([]byte(tt.data))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_parseJSON5(t *testing.T) {
+	type InStruct struct {
+		Field string `json:"field"`
+		Int   int    `json:"int"`
+	}
+
+	tests := []struct {
+		name       string
+		data       string
+		wantStruct *InStruct
+		wantErr    string
+	}{
+		{
+			name:       "Happy Path",
+			data:       "{\n  field: 'value', // comment\n  int: 42,\n}",
+			wantStruct: &InStruct{Field: "value", Int: 42},
+		},
+		{
+			name:       "Syntax Error Line",
+			data:       "{\n  // comment\n  field: 'value'\n  int: 42\n}",
+			wantStruct: &InStruct{},
+			wantErr:    "line 4: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			structPtr := &InStruct{}
+			err := parseJSON5(strings.NewReader(tt.data), structPtr, options.Options{})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStruct, structPtr)
+		})
+	}
+}
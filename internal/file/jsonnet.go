@@ -0,0 +1,59 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-jsonnet"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// evaluateJsonnet is a helper function used by Read to evaluate the Jsonnet content of
+// the file into JSON, which is then parsed the same way as the content of JSON files.
+// Imports are resolved relative to the file. The environment variables and command-line
+// flags known to the struct are available as external variables, so std.extVar("HTTP_PORT")
+// and std.extVar("http-port") return the values they are set to, or an empty string if they
+// are not set. The function returns an error if the evaluation fails.
+func evaluateJsonnet(path string, data []byte, structPtr any, opts options.Options) ([]byte, error) {
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.FileImporter{JPaths: []string{filepath.Dir(path)}})
+	for name, value := range externalVariables(structPtr, opts) {
+		vm.ExtVar(name, value)
+	}
+
+	result, err := vm.EvaluateAnonymousSnippet(path, string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(result), nil
+}
+
+// externalVariables is a helper function used by evaluateJsonnet to collect the values
+// of the environment variables and the command-line flags of the fields. Since Jsonnet
+// fails on the external variables that are not defined, the variables and flags that are
+// not set are returned with empty values.
+func externalVariables(structPtr any, opts options.Options) map[string]string {
+	result := make(map[string]string)
+
+	var flags []string
+	for _, keys := range reflect.FieldKeys(structPtr, opts.Naming) {
+		if keys.Env != "" {
+			result[keys.Env] = os.Getenv(keys.Env)
+		}
+		if keys.Flag != "" {
+			flags = append(flags, keys.Flag)
+			if _, ok := result[keys.Flag]; !ok {
+				result[keys.Flag] = ""
+			}
+		}
+	}
+
+	for name, value := range lookupFlags(flags...) {
+		result[name] = value
+	}
+
+	return result
+}
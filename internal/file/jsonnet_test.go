@@ -0,0 +1,68 @@
+package file
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+func Test_evaluateJsonnet(t *testing.T) {
+	type InStruct struct {
+		Replicas int    `json:"replicas" env:"REPLICAS"`
+		Region   string `json:"region" flag:"region"`
+		Mode     string `cfg:"mode"`
+		Port     int    `json:"port" env:"JSONNET_PORT" flag:"port"`
+	}
+
+	t.Setenv("REPLICAS", "3")
+	t.Setenv("MODE", "prod")
+	args := os.Args
+	os.Args = []string{"app", "--region=eu", "--unknown", "value"}
+	defer func() { os.Args = args }()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "External Variables",
+			data: `{
+				replicas: std.parseInt(std.extVar('REPLICAS')) * 2,
+				region: std.extVar('region'),
+				mode: std.extVar('MODE'),
+			}`,
+			want: `{"mode": "prod", "region": "eu", "replicas": 6}`,
+		},
+		{
+			name: "Unset Variables",
+			data: `local port = std.extVar('JSONNET_PORT');
+			{
+				[if port != '' then 'port']: std.parseInt(port),
+				flag: std.extVar('port'),
+			}`,
+			want: `{"flag": ""}`,
+		},
+		{
+			name:    "Unknown Variable",
+			data:    `{ region: std.extVar('UNKNOWN') }`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evaluateJsonnet("cfg.jsonnet", []byte(tt.data), &InStruct{}, options.Options{})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
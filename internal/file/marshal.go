@@ -0,0 +1,356 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	rf "reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+// leaf is a value of the configuration to be marshaled.
+type leaf struct {
+	field string   // The fully qualified name of the field, e.g. HTTP.Port.
+	keys  []string // The path of keys of the value in the configuration file.
+	value any      // The value of the field, with the secrets already redacted or revealed.
+
+	// The descriptions of the fields of the keys, taken from the description tags.
+	descriptions []string
+}
+
+// tree is an ordered mapping of the keys of a configuration file to their values, which
+// are either trees or plain values.
+type tree struct {
+	keys         []string
+	values       map[string]any
+	descriptions map[string]string
+}
+
+// Marshal is a function that encodes the configuration in the cfg structure in the format,
+// which is json, yaml, toml or env, with the same keys the file reader reads. The values of
+// the fields that hold secrets are replaced with secret.Redacted unless the options reveal
+// them. The function returns ErrUnsupportedFormat for other formats.
+func Marshal(structPtr any, format string, opts ...options.Option) ([]byte, error) {
+	if err := reflect.Validation(structPtr); err != nil {
+		return nil, fmt.Errorf("error validating struct: %w", err)
+	}
+
+	return marshal(structPtr, format, options.New(opts...), nil)
+}
+
+// marshal is a helper function used by Marshal and Convert to encode the fields of the
+// struct in the format. If the fields parameter is not nil, only the fields in it are written.
+func marshal(structPtr any, format string, opts options.Options, fields map[string]bool) ([]byte, error) {
+	list := func(tag string) []leaf {
+		result := leaves(structPtr, tag, opts)
+		if fields == nil {
+			return result
+		}
+
+		kept := result[:0]
+		for _, l := range result {
+			if fields[l.field] {
+				kept = append(kept, l)
+			}
+		}
+		return kept
+	}
+
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "json":
+		return marshalJSON(newTree(list("json")))
+	case "yaml", "yml":
+		return marshalYAML(newTree(list("yaml")), false)
+	case "toml":
+		return marshalTOML(newTree(list("toml")))
+	case "env":
+		return marshalENV(envVariables(structPtr, list("env"), opts))
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// leaves is a helper function used by Marshal that lists the values of the exported fields
+// of the struct in their order, with the paths of keys the format maps them to.
+func leaves(structPtr any, tag string, opts options.Options) []leaf {
+	secrets := secret.Fields(rf.TypeOf(structPtr))
+
+	var result []leaf
+	leavesRecursive(rf.ValueOf(structPtr).Elem(), tag, opts, secrets, "", nil, nil, &result)
+	return result
+}
+
+// leavesRecursive is a helper function for leaves. The prefix parameter is used to build
+// the fully qualified names of the fields, and the parentKeys and parentDescriptions
+// parameters hold the path of keys of the struct the fields belong to and their descriptions.
+func leavesRecursive(
+	valueOf rf.Value,
+	tag string,
+	opts options.Options,
+	secrets map[string]bool,
+	prefix string,
+	parentKeys []string,
+	parentDescriptions []string,
+	result *[]leaf,
+) {
+	for i := 0; i < valueOf.NumField(); i++ {
+		field, value := valueOf.Type().Field(i), valueOf.Field(i)
+		if field.Tag.Get(tag) == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+
+		name := prefix + field.Name
+		if field.Anonymous && field.Type.Kind() == rf.Struct && reflect.Segment(field, nil) == "" && field.Tag.Get(tag) == "" {
+			leavesRecursive(value, tag, opts, secrets, name+".", parentKeys, parentDescriptions, result)
+			continue
+		}
+
+		fileKey := reflect.FileKey(field, tag, opts.Naming)
+		keys := append(parentKeys[:len(parentKeys):len(parentKeys)], fileKey...)
+		descriptions := append(parentDescriptions[:len(parentDescriptions):len(parentDescriptions)], make([]string, len(fileKey))...)
+		descriptions[len(descriptions)-1] = field.Tag.Get("description")
+
+		if field.Type.Kind() == rf.Struct && !reflect.IsText(field.Type) {
+			leavesRecursive(value, tag, opts, secrets, name+".", keys, descriptions, result)
+			continue
+		}
+
+		if field.Type.Kind() == rf.Ptr && value.IsNil() || !value.CanInterface() {
+			continue
+		}
+
+		*result = append(*result, leaf{
+			field:        name,
+			keys:         keys,
+			value:        leafValue(value.Interface(), tag, secrets[name], opts),
+			descriptions: descriptions,
+		})
+	}
+}
+
+// leafValue is a helper function that returns the value of a field to be marshaled. The
+// values of the secrets are redacted unless the options reveal them. Durations are written
+// as strings, e.g. 1m30s, for the formats whose decoders read them so, and as nanoseconds
+// for the others.
+func leafValue(value any, tag string, isSecret bool, opts options.Options) any {
+	if isSecret && !opts.RevealSecrets {
+		return secret.Redacted
+	}
+
+	switch v := value.(type) {
+	case secret.Secret:
+		return v.Reveal()
+	case time.Duration:
+		if tag == "yaml" || tag == "toml" {
+			return v.String()
+		}
+		return int64(v)
+	}
+	return value
+}
+
+// newTree is a helper function that builds the tree of the keys of the leaves.
+func newTree(leaves []leaf) *tree {
+	root := newSubtree()
+	for _, l := range leaves {
+		current := root
+		for i, key := range l.keys[:len(l.keys)-1] {
+			child, ok := current.values[key].(*tree)
+			if !ok {
+				child = newSubtree()
+				current.set(key, child, l.descriptions[i])
+			}
+			current = child
+		}
+		current.set(l.keys[len(l.keys)-1], l.value, l.descriptions[len(l.keys)-1])
+	}
+	return root
+}
+
+// newSubtree is a helper function that returns an empty tree.
+func newSubtree() *tree {
+	return &tree{values: map[string]any{}, descriptions: map[string]string{}}
+}
+
+// set is a helper method that sets the value of the key and its description, keeping the
+// order of the keys.
+func (t *tree) set(key string, value any, description string) {
+	if _, ok := t.values[key]; !ok {
+		t.keys = append(t.keys, key)
+	}
+	t.values[key] = value
+	if description != "" {
+		t.descriptions[key] = description
+	}
+}
+
+// MarshalJSON implements json.Marshaler and writes the keys of the tree in their order.
+func (t *tree) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range t.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		keyData, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueData, err := json.Marshal(t.values[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", key, err)
+		}
+
+		buf.Write(keyData)
+		buf.WriteByte(':')
+		buf.Write(valueData)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// plain is a helper method that returns the tree as nested maps.
+func (t *tree) plain() map[string]any {
+	result := make(map[string]any, len(t.keys))
+	for key, value := range t.values {
+		if child, ok := value.(*tree); ok {
+			value = child.plain()
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// node is a helper method that returns the tree as a YAML mapping node. The descriptions
+// of the keys are written as their comments if the commented parameter is set.
+func (t *tree) node(commented bool) (*yaml.Node, error) {
+	result := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range t.keys {
+		valueNode := &yaml.Node{}
+		if child, ok := t.values[key].(*tree); ok {
+			var err error
+			if valueNode, err = child.node(commented); err != nil {
+				return nil, err
+			}
+		} else if err := valueNode.Encode(t.values[key]); err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", key, err)
+		}
+
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: key}
+		if commented {
+			keyNode.HeadComment = comment(t.descriptions[key])
+		}
+		result.Content = append(result.Content, keyNode, valueNode)
+	}
+	return result, nil
+}
+
+// marshalJSON is a helper function used by Marshal to encode the tree as indented JSON.
+func marshalJSON(t *tree) ([]byte, error) {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// marshalYAML is a helper function used by Marshal to encode the tree as YAML. The
+// descriptions of the keys are written as their comments if the commented parameter is set.
+func marshalYAML(t *tree, commented bool) ([]byte, error) {
+	node, err := t.node(commented)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal yaml: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(node); err != nil {
+		return nil, fmt.Errorf("failed to marshal yaml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalTOML is a helper function used by Marshal to encode the tree as TOML, where the
+// keys are sorted and the plain values come before the tables.
+func marshalTOML(t *tree) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(t.plain()); err != nil {
+		return nil, fmt.Errorf("failed to marshal toml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// envVariable is an environment variable of a field to be marshaled.
+type envVariable struct {
+	name        string
+	value       string
+	description string
+}
+
+// envVariables is a helper function that returns the environment variables of the fields
+// of the leaves in their order. Slices are joined with commas and maps are written as
+// key:value pairs, the way the environment reader reads them.
+func envVariables(structPtr any, leaves []leaf, opts options.Options) []envVariable {
+	fieldKeys := reflect.FieldKeys(structPtr, opts.Naming)
+
+	result := make([]envVariable, 0, len(leaves))
+	for _, l := range leaves {
+		if name := fieldKeys[l.field].Env; name != "" {
+			result = append(result, envVariable{
+				name:        name,
+				value:       envValue(l.value),
+				description: l.descriptions[len(l.descriptions)-1],
+			})
+		}
+	}
+	return result
+}
+
+// marshalENV is a helper function used by Marshal to encode the environment variables
+// sorted by their names.
+func marshalENV(variables []envVariable) ([]byte, error) {
+	values := make(map[string]string, len(variables))
+	for _, variable := range variables {
+		values[variable.name] = variable.value
+	}
+
+	data, err := godotenv.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal env: %w", err)
+	}
+	return []byte(data + "\n"), nil
+}
+
+// envValue is a helper function used by marshalENV to format the value of a variable.
+func envValue(value any) string {
+	valueOf := rf.ValueOf(value)
+	switch valueOf.Kind() {
+	case rf.Slice, rf.Array:
+		items := make([]string, valueOf.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(valueOf.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	case rf.Map:
+		items := make([]string, 0, valueOf.Len())
+		for _, key := range valueOf.MapKeys() {
+			items = append(items, fmt.Sprintf("%v:%v", key.Interface(), valueOf.MapIndex(key).Interface()))
+		}
+		sort.Strings(items)
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(value)
+	}
+}
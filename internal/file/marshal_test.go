@@ -0,0 +1,176 @@
+package file
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+type marshalStructNested struct {
+	Host    string        `json:"host" yaml:"host" toml:"host" env:"HTTP_HOST"`
+	Port    int           `json:"port" yaml:"port" toml:"port" env:"HTTP_PORT"`
+	Timeout time.Duration `json:"timeout" yaml:"timeout" toml:"timeout" env:"HTTP_TIMEOUT"`
+}
+
+type marshalStruct struct {
+	Mode     string              `json:"mode" yaml:"mode" toml:"mode" env:"MODE"`
+	Tags     []string            `json:"tags" yaml:"tags" toml:"tags" env:"TAGS"`
+	HTTP     marshalStructNested `json:"http" yaml:"http" toml:"http"`
+	Password secret.Secret       `json:"password" yaml:"password" toml:"password" env:"PASSWORD"`
+	Token    string              `json:"token" yaml:"token" toml:"token" env:"TOKEN" secret:"true"`
+	Skipped  string              `json:"-" yaml:"-" toml:"-" env:"-"`
+	private  string
+}
+
+func newMarshalStruct() *marshalStruct {
+	return &marshalStruct{
+		Mode:     "prod",
+		Tags:     []string{"a", "b"},
+		HTTP:     marshalStructNested{Host: "localhost", Port: 8080, Timeout: 90 * time.Second},
+		Password: "hunter2",
+		Token:    "swordfish",
+		Skipped:  "skipped",
+		private:  "private",
+	}
+}
+
+func Test_Marshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "JSON",
+			format: "json",
+			want: `{
+  "mode": "prod",
+  "tags": [
+    "a",
+    "b"
+  ],
+  "http": {
+    "host": "localhost",
+    "port": 8080,
+    "timeout": 90000000000
+  },
+  "password": "[REDACTED]",
+  "token": "[REDACTED]"
+}
+`,
+		},
+		{
+			name:   "YAML",
+			format: ".yml",
+			want: `mode: prod
+tags:
+  - a
+  - b
+http:
+  host: localhost
+  port: 8080
+  timeout: 1m30s
+password: '[REDACTED]'
+token: '[REDACTED]'
+`,
+		},
+		{
+			name:   "TOML",
+			format: "TOML",
+			want: `mode = "prod"
+password = "[REDACTED]"
+tags = ["a", "b"]
+token = "[REDACTED]"
+
+[http]
+  host = "localhost"
+  port = 8080
+  timeout = "1m30s"
+`,
+		},
+		{
+			name:   "ENV",
+			format: "env",
+			want: `HTTP_HOST="localhost"
+HTTP_PORT=8080
+HTTP_TIMEOUT=90000000000
+MODE="prod"
+PASSWORD="[REDACTED]"
+TAGS="a,b"
+TOKEN="[REDACTED]"
+`,
+		},
+		{
+			name:    "Unsupported",
+			format:  "ini",
+			wantErr: ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Marshal(newMarshalStruct(), tt.format)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
+
+func Test_Marshal_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	reveal := func(o *options.Options) { o.RevealSecrets = true }
+
+	for _, format := range []string{"json", "yaml", "toml", "env"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := Marshal(newMarshalStruct(), format, reveal)
+			assert.NoError(t, err)
+
+			filePath := path.Join(dir, "cfg."+format)
+			assert.NoError(t, os.WriteFile(filePath, data, 0o600))
+
+			want := newMarshalStruct()
+			want.Skipped, want.private = "", ""
+			if format == "env" {
+				// The .env reader only writes plain values to the fields.
+				want.Tags = nil
+			}
+
+			structPtr := &marshalStruct{}
+			assert.NoError(t, Read(filePath, structPtr))
+			assert.Equal(t, want, structPtr)
+		})
+	}
+}
+
+func Test_Marshal_Canonical(t *testing.T) {
+	type InStruct struct {
+		ReadTimeout int
+		HTTP        struct {
+			Port int `cfg:"server.port"`
+		} `cfg:"http"`
+	}
+
+	structPtr := &InStruct{ReadTimeout: 5}
+	structPtr.HTTP.Port = 8080
+
+	data, err := Marshal(structPtr, "yaml", func(o *options.Options) { o.Naming = reflect.SnakeCase })
+	assert.NoError(t, err)
+	assert.Equal(t, "read_timeout: 5\nhttp:\n  server:\n    port: 8080\n", string(data))
+
+	data, err = Marshal(structPtr, "env", func(o *options.Options) { o.Naming = reflect.SnakeCase })
+	assert.NoError(t, err)
+	assert.Equal(t, "HTTP_SERVER_PORT=8080\nREAD_TIMEOUT=5\n", string(data))
+}
@@ -0,0 +1,288 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/hashicorp/hcl"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+// profilesKey is the top-level key that holds the inline sections of the profiles.
+const profilesKey = "profiles"
+
+// resolveProfile is a helper function used by Read to find the active profile. The
+// profile set with the options takes precedence, then the command-line flag and finally
+// the environment variable the options name. It returns an empty string if no profile
+// is active.
+func resolveProfile(opts options.Options) string {
+	if opts.Profile != "" {
+		return opts.Profile
+	}
+
+	if opts.ProfileFlag != "" {
+		if value, ok := lookupFlags(opts.ProfileFlag)[opts.ProfileFlag]; ok && value != "" {
+			return value
+		}
+	}
+
+	if opts.ProfileEnv != "" {
+		return os.Getenv(opts.ProfileEnv)
+	}
+
+	return ""
+}
+
+// lookupFlags is a helper function that returns the values of the command-line flags
+// with the given names that are set. Other flags are ignored, since they are reported
+// when the flags are read.
+func lookupFlags(names ...string) map[string]string {
+	flagSet := pflag.NewFlagSet("file", pflag.ContinueOnError)
+	flagSet.ParseErrorsWhitelist.UnknownFlags = true
+	flagSet.SetOutput(io.Discard)
+	flagSet.Usage = func() {}
+
+	for _, name := range names {
+		if flagSet.Lookup(name) == nil {
+			flagSet.String(name, "", "")
+		}
+	}
+
+	result := make(map[string]string)
+	_ = flagSet.Parse(os.Args[1:])
+	flagSet.Visit(func(f *pflag.Flag) {
+		result[f.Name] = f.Value.String()
+	})
+
+	return result
+}
+
+// profilePath is a helper function that returns the path of the file of the profile,
+// which is the path of the base file with the profile inserted before the extension,
+// e.g. config.prod.yaml for config.yaml.
+func profilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.%s%s", strings.TrimSuffix(path, ext), profile, ext)
+}
+
+// readProfileFile is a helper function used by Read to read the file of the profile over
+// the base file. A missing file of the profile is not an error.
+func readProfileFile(path, profile string, structPtr any, opts options.Options) error {
+	path = profilePath(path, profile)
+	if opts.OnRead != nil {
+		opts.OnRead(path)
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return readFile(path, structPtr, opts, nil)
+}
+
+// profileJSON is a helper function used by readFile to extract the section of the
+// profile from the JSON content. It returns nil if the content has no such section.
+func profileJSON(data []byte, profile string) ([]byte, error) {
+	var document struct {
+		Profiles map[string]json.RawMessage `json:"profiles"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return document.Profiles[profile], nil
+}
+
+// profileJSON5 is a helper function used by readFile to extract the section of the
+// profile from the JSON5 or JSONC content.
+func profileJSON5(data []byte, profile string) ([]byte, error) {
+	data, err := standardizeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return profileJSON(data, profile)
+}
+
+// profileYAML is a helper function used by readFile to extract the section of the
+// profile from the YAML content.
+func profileYAML(data []byte, profile string) ([]byte, error) {
+	var document struct {
+		Profiles map[string]yaml.Node `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	section, ok := document.Profiles[profile]
+	if !ok {
+		return nil, nil
+	}
+	return yaml.Marshal(&section)
+}
+
+// profileTOML is a helper function used by readFile to extract the section of the
+// profile from the TOML content.
+func profileTOML(data []byte, profile string) ([]byte, error) {
+	var document struct {
+		Profiles map[string]map[string]any `toml:"profiles"`
+	}
+	if _, err := toml.Decode(string(data), &document); err != nil {
+		return nil, err
+	}
+
+	section, ok := document.Profiles[profile]
+	if !ok {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(section); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// profileHCL is a helper function used by readFile to extract the section of the
+// profile from the HCL content. The section is returned as JSON, which the HCL
+// decoder accepts as well.
+func profileHCL(data []byte, profile string) ([]byte, error) {
+	var document map[string]any
+	if err := hcl.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	profiles, _ := mergeBlocks(document).(map[string]any)[profilesKey].(map[string]any)
+	section, ok := profiles[profile]
+	if !ok {
+		return nil, nil
+	}
+	return json.Marshal(section)
+}
+
+// profileEntries is a helper function that returns the entries of a flat configuration
+// file that belong to the section of the profile, with the prefix of the section removed.
+func profileEntries(entries []entry, profile string) []entry {
+	prefix := strings.ToLower(profilesKey + "." + profile + ".")
+
+	var result []entry
+	for _, e := range entries {
+		if strings.HasPrefix(strings.ToLower(e.key), prefix) {
+			e.key = e.key[len(prefix):]
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// profileINI is a helper function used by readFile to extract the section of the
+// profile from the INI content, where the keys of the profile are in the sections
+// prefixed with profiles and its name, e.g. [profiles.prod.http].
+func profileINI(data []byte, profile string) ([]byte, error) {
+	entries, err := entriesINI(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	entries = profileEntries(entries, profile)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	sections := map[string][]string{}
+	for _, e := range entries {
+		section, key := "", e.key
+		if i := strings.LastIndex(e.key, "."); i >= 0 {
+			section, key = e.key[:i], e.key[i+1:]
+		}
+		sections[section] = append(sections[section], fmt.Sprintf("%s = \"%s\"", key, e.value))
+	}
+
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		if name != "" {
+			fmt.Fprintf(&buf, "[%s]\n", name)
+		}
+		fmt.Fprintln(&buf, strings.Join(sections[name], "\n"))
+	}
+	return buf.Bytes(), nil
+}
+
+// profileProperties is a helper function used by readFile to extract the section of the
+// profile from the .properties content, where the keys of the profile are prefixed with
+// profiles and its name, e.g. profiles.prod.http.port.
+func profileProperties(data []byte, profile string) ([]byte, error) {
+	entries, err := entriesProperties(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	entries = profileEntries(entries, profile)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	keyEscaper := strings.NewReplacer(`\`, `\\`, " ", `\ `, "=", `\=`, ":", `\:`)
+	valueEscaper := strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	var buf bytes.Buffer
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "%s=%s\n", keyEscaper.Replace(e.key), valueEscaper.Replace(e.value))
+	}
+	return buf.Bytes(), nil
+}
+
+// profileXML is a helper function used by readFile to extract the section of the
+// profile from the XML content. The element of the profile inside the profiles element
+// becomes the root element of the returned content.
+func profileXML(data []byte, profile string) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var depth int
+	var inProfiles bool
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 && strings.EqualFold(t.Name.Local, profilesKey) {
+				inProfiles = true
+				continue
+			}
+			if depth == 3 && inProfiles && t.Name.Local == profile {
+				if err = decoder.Skip(); err != nil {
+					return nil, err
+				}
+				return data[start:decoder.InputOffset()], nil
+			}
+		case xml.EndElement:
+			if depth == 2 {
+				inProfiles = false
+			}
+			depth--
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package file
+
+import (
+	"fmt"
+	rf "reflect"
+	"strings"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// trackFields is a helper function used by readFile to report the fields the file sets
+// to the Track callback of the options. The location of a field is the path of the file
+// and the line of its key, which is left out for evaluated formats, since the lines of
+// the evaluated content do not match the file. The value is the raw value of the key as
+// the file has it, e.g. 1m30s for a duration. The values of the inline section of the
+// active profile are reported after the values of the file.
+func trackFields(path string, f format, data []byte, structPtr any, opts options.Options) error {
+	if f.nodes == nil {
+		return nil
+	}
+
+	nodes, err := f.nodes(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", f.name, err)
+	}
+
+	found := map[string]node{}
+	var order []string
+	record := func(field string, n node) {
+		if _, ok := found[field]; !ok {
+			order = append(order, field)
+		}
+		found[field] = n
+	}
+
+	if f.tag == "env" {
+		fieldNodesFlat(nodes, structPtr, opts.Naming, record)
+	} else {
+		typeOf := rf.TypeOf(structPtr).Elem()
+		fieldNodes(nodes, typeOf, f.tag, opts.Naming, "", record)
+
+		if opts.Profile != "" {
+			if profiles, ok := findNode(nodes, profilesKey, f.tag); ok {
+				if section, found := findNode(profiles.children, opts.Profile, "yaml"); found {
+					fieldNodes(section.children, typeOf, f.tag, opts.Naming, "", record)
+				}
+			}
+		}
+	}
+
+	for _, field := range order {
+		n := found[field]
+		location := path
+		if n.line > 0 && f.evaluate == nil {
+			location = fmt.Sprintf("%s:%d", path, n.line)
+		}
+
+		value := n.value
+		if value == "" && len(n.children) > 0 {
+			value = fieldValue(structPtr, field)
+		}
+		opts.Track(field, location, value)
+	}
+
+	return nil
+}
+
+// fieldNodes is a helper function used by trackFields to walk the nodes alongside the
+// fields of the struct type the same way the strict mode does. The record function is
+// called with the fully qualified name and the node of every field that has a key.
+func fieldNodes(
+	nodes []node,
+	typeOf rf.Type,
+	tag string,
+	naming func(string) string,
+	prefix string,
+	record func(field string, n node),
+) {
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if field.Tag.Get(tag) == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+
+		name := prefix + field.Name
+		if field.Anonymous && field.Type.Kind() == rf.Struct && reflect.Segment(field, nil) == "" && field.Tag.Get(tag) == "" {
+			fieldNodes(nodes, field.Type, tag, naming, name+".", record)
+			continue
+		}
+
+		current, found := node{children: nodes}, true
+		for _, key := range reflect.FileKey(field, tag, naming) {
+			if current, found = findNode(current.children, key, tag); !found {
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		if field.Type.Kind() == rf.Struct && !reflect.IsText(field.Type) {
+			fieldNodes(current.children, field.Type, tag, naming, name+".", record)
+		} else {
+			record(name, current)
+		}
+	}
+}
+
+// fieldNodesFlat is a helper function used by trackFields for .env files, where every
+// field is mapped to a single environment variable.
+func fieldNodesFlat(nodes []node, structPtr any, naming func(string) string, record func(field string, n node)) {
+	byKey := make(map[string]node, len(nodes))
+	for _, n := range nodes {
+		byKey[n.key] = n
+	}
+
+	for field, keys := range reflect.FieldKeys(structPtr, naming) {
+		if n, ok := byKey[keys.Env]; ok && keys.Env != "" {
+			record(field, n)
+		}
+	}
+}
+
+// findNode is a helper function that finds the node of the key. YAML keys must match
+// exactly, while the keys of the other formats also match case-insensitively like their
+// decoders do.
+func findNode(nodes []node, key, tag string) (node, bool) {
+	for _, n := range nodes {
+		if n.key == key {
+			return n, true
+		}
+	}
+
+	if tag != "yaml" {
+		for _, n := range nodes {
+			if strings.EqualFold(n.key, key) {
+				return n, true
+			}
+		}
+	}
+
+	return node{}, false
+}
+
+// fieldValue is a helper function used by trackFields that returns the value of the field
+// with the fully qualified name as a string, for the fields whose keys hold objects, e.g.
+// maps, which have no raw value of their own.
+func fieldValue(structPtr any, field string) string {
+	value := rf.ValueOf(structPtr).Elem()
+	for _, name := range strings.Split(field, ".") {
+		value = value.FieldByName(name)
+	}
+
+	if !value.IsValid() || !value.CanInterface() {
+		return ""
+	}
+	return fmt.Sprint(value.Interface())
+}
@@ -0,0 +1,165 @@
+package file
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+func Test_Read_Track(t *testing.T) {
+	type InStructNested struct {
+		Field string `json:"field" yaml:"field" toml:"field" xml:"field" env:"NESTED_FIELD"`
+	}
+	type InStruct struct {
+		Field  string         `json:"field" yaml:"field" toml:"field" xml:"field" env:"FIELD"`
+		Nested InStructNested `json:"nested" yaml:"nested" toml:"nested" xml:"nested"`
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		profile string
+		want    map[string][2]string
+	}{
+		{
+			name: "YAML",
+			path: path.Join("tests", "cfg.yaml"),
+			want: map[string][2]string{
+				"Field":        {path.Join("tests", "cfg.yaml") + ":1", "yamlFieldValue"},
+				"Nested.Field": {path.Join("tests", "cfg.yaml") + ":3", "yamlNestedFieldValue"},
+			},
+		},
+		{
+			name: "TOML",
+			path: path.Join("tests", "cfg.toml"),
+			want: map[string][2]string{
+				"Field":        {path.Join("tests", "cfg.toml") + ":1", "tomlFieldValue"},
+				"Nested.Field": {path.Join("tests", "cfg.toml") + ":4", "tomlNestedFieldValue"},
+			},
+		},
+		{
+			name: "XML",
+			path: path.Join("tests", "cfg.xml"),
+			want: map[string][2]string{
+				"Field":        {path.Join("tests", "cfg.xml") + ":4", "xmlFieldValue"},
+				"Nested.Field": {path.Join("tests", "cfg.xml") + ":5", "xmlNestedFieldValue"},
+			},
+		},
+		{
+			name: "ENV",
+			path: path.Join("tests", "cfg.env"),
+			want: map[string][2]string{
+				"Field":        {path.Join("tests", "cfg.env") + ":1", "envFieldValue"},
+				"Nested.Field": {path.Join("tests", "cfg.env") + ":2", "envNestedFieldValue"},
+			},
+		},
+		{
+			name: "Jsonnet Without Lines",
+			path: path.Join("tests", "cfg.jsonnet"),
+			want: map[string][2]string{
+				"Field":        {path.Join("tests", "cfg.jsonnet"), "jsonnetFieldValue"},
+				"Nested.Field": {path.Join("tests", "cfg.jsonnet"), "jsonnetNestedFieldValue"},
+			},
+		},
+		{
+			name: "Include",
+			path: path.Join("tests", "include.yaml"),
+			want: map[string][2]string{
+				"Field":        {path.Join("tests", "include.yaml") + ":2", "yamlIncludeFieldValue"},
+				"Nested.Field": {path.Join("tests", "include-nested.json") + ":4", "jsonIncludeNestedFieldValue"},
+			},
+		},
+		{
+			name:    "Profile",
+			path:    path.Join("tests", "profile.yaml"),
+			profile: "prod",
+			want: map[string][2]string{
+				"Field":        {path.Join("tests", "profile.yaml") + ":6", "prodFieldValue"},
+				"Nested.Field": {path.Join("tests", "profile.prod.yaml") + ":2", "prodFileNestedFieldValue"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string][2]string{}
+			err := Read(tt.path, &InStruct{}, func(o *options.Options) {
+				o.Profile = tt.profile
+				o.Track = func(field, location, value string) {
+					got[field] = [2]string{location, value}
+				}
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Read_Track_RawValues(t *testing.T) {
+	type InStruct struct {
+		Timeout time.Duration `json:"timeout" yaml:"timeout" toml:"timeout" hcl:"timeout" env:"TIMEOUT"`
+		Tags    []string      `json:"tags" yaml:"tags" toml:"tags" hcl:"tags"`
+		Ratio   float64       `json:"ratio" yaml:"ratio" toml:"ratio" hcl:"ratio" env:"RATIO"`
+	}
+
+	tests := []struct {
+		name string
+		file string
+		data string
+		want map[string]string
+	}{
+		{
+			name: "YAML",
+			file: "cfg.yaml",
+			data: "timeout: 1m30s\ntags:\n  - a\n  - b\nratio: 0.50\n",
+			want: map[string]string{"Timeout": "1m30s", "Tags": "[a, b]", "Ratio": "0.50"},
+		},
+		{
+			name: "JSON",
+			file: "cfg.json",
+			data: `{"timeout": 90000000000, "tags": ["a", "b"], "ratio": 0.50}`,
+			want: map[string]string{"Timeout": "90000000000", "Tags": `["a", "b"]`, "Ratio": "0.50"},
+		},
+		{
+			name: "TOML",
+			file: "cfg.toml",
+			data: "timeout = \"1m30s\"\ntags = [\"a\", \"b\"]\nratio = 0.5\n",
+			want: map[string]string{"Timeout": "1m30s", "Tags": `["a", "b"]`, "Ratio": "0.5"},
+		},
+		{
+			name: "HCL",
+			file: "cfg.hcl",
+			data: "timeout = 90000000000\ntags = [\"a\", \"b\"]\nratio = 0.50\n",
+			want: map[string]string{"Timeout": "90000000000", "Tags": `["a", "b"]`, "Ratio": "0.50"},
+		},
+		{
+			name: "ENV",
+			file: "cfg.env",
+			data: "TIMEOUT=90000000000\nRATIO=0.50\n",
+			want: map[string]string{"Timeout": "90000000000", "Ratio": "0.50"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := path.Join(t.TempDir(), tt.file)
+			assert.NoError(t, os.WriteFile(filePath, []byte(tt.data), 0o600))
+
+			got := map[string]string{}
+			err := Read(filePath, &InStruct{}, func(o *options.Options) {
+				o.Track = func(field, _, value string) {
+					got[field] = value
+				}
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
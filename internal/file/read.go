@@ -1,27 +1,116 @@
 package file
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	rf "reflect"
 	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
 
+	"github.com/dsbasko/go-cfg/internal/crypt"
 	"github.com/dsbasko/go-cfg/internal/dflt"
+	"github.com/dsbasko/go-cfg/internal/options"
 	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/secret"
 )
 
+// includeKey is the top-level key that lists the files included by a configuration file.
+const includeKey = "$include"
+
+// format describes how the content of a configuration file is parsed.
+type format struct {
+	name     string                                                       // The name of the format used in error messages.
+	parse    func(r io.Reader, structPtr any, opts options.Options) error // Decodes the content into the struct.
+	includes func(data []byte) ([]string, error)                          // Lists the files included by the content.
+	nodes    func(data []byte) ([]node, error)                            // Lists the keys of the content for the strict mode.
+	document func(data []byte) (any, error)                               // Decodes the content into plain values for the schema.
+	tag      string                                                       // The struct tag that maps the keys to the fields.
+
+	// Extracts the inline section of the profile from the content, or returns nil if there is none.
+	profile func(data []byte, profile string) ([]byte, error)
+
+	// Turns the content into the content of the format before it is parsed, e.g. Jsonnet into JSON.
+	evaluate func(path string, data []byte, structPtr any, opts options.Options) ([]byte, error)
+}
+
+// formats maps the supported file extensions to their formats.
+var formats = map[string]format{
+	".json": {
+		name: "json", tag: "json", parse: parseJSON, nodes: nodesJSON, document: documentJSON,
+		includes: includesJSON, profile: profileJSON,
+	},
+	".json5": {
+		name: "json5", tag: "json", parse: parseJSON5, nodes: nodesJSON5, document: documentJSON5,
+		includes: includesJSON5, profile: profileJSON5,
+	},
+	".jsonc": {
+		name: "jsonc", tag: "json", parse: parseJSON5, nodes: nodesJSON5, document: documentJSON5,
+		includes: includesJSON5, profile: profileJSON5,
+	},
+	".jsonnet": {
+		name: "jsonnet", tag: "json", parse: parseJSON, nodes: nodesJSON, document: documentJSON,
+		includes: includesJSON, profile: profileJSON, evaluate: evaluateJsonnet,
+	},
+	".yaml": {
+		name: "yaml", tag: "yaml", parse: parseYAML, nodes: nodesYAML, document: documentYAML,
+		includes: includesYAML, profile: profileYAML,
+	},
+	".yml": {
+		name: "yaml", tag: "yaml", parse: parseYAML, nodes: nodesYAML, document: documentYAML,
+		includes: includesYAML, profile: profileYAML,
+	},
+	".toml": {
+		name: "toml", tag: "toml", parse: parseTOML, nodes: nodesTOML, document: documentTOML,
+		includes: includesTOML, profile: profileTOML,
+	},
+	".hcl": {
+		name: "hcl", tag: "hcl", parse: parseHCL, nodes: nodesHCL,
+		includes: includesHCL, profile: profileHCL,
+	},
+	".ini": {
+		name: "ini", tag: "ini", parse: parseINI, nodes: nodesINI,
+		includes: includesINI, profile: profileINI,
+	},
+	".properties": {
+		name: "properties", tag: "properties", parse: parseProperties, nodes: nodesProperties,
+		includes: includesProperties, profile: profileProperties,
+	},
+	".xml": {
+		name: "xml", tag: "xml", parse: parseXML, nodes: nodesXML,
+		includes: includesXML, profile: profileXML,
+	},
+	".env": {
+		name: "env", tag: "env", parse: parseENV, nodes: nodesENV,
+	},
+}
+
 // Read is a function that parses the content of the file into the provided cfg structure.
 // The path parameter should be a string representing the path to the file. The structPtr
 // parameter should be a pointer to a struct where each field represents a configuration
 // option. The function returns an error if the parsing process fails, wrapping the
 // original error with a message.
-func Read(path string, structPtr any) error {
+//
+// Files listed under the top-level "$include" key are read before the file itself, so the
+// including file overrides the values of the files it includes. In the strict mode keys
+// that do not match any field are reported as ErrUnknownKey, and with the schema validation
+// the values that do not match the schema of the struct as ErrSchemaViolation. JSON5 and JSONC files, and JSON
+// files in the lenient mode, may contain comments, trailing commas and unquoted keys. Jsonnet
+// files are evaluated into JSON first.
+//
+// If a profile is active, the section of the profile under the top-level "profiles" key of
+// every file is read over the file, and the file of the profile, e.g. config.prod.yaml for
+// config.yaml, is read over the base file if it exists.
+//
+// Finally the encrypted string values, e.g. ENC[...], are decrypted with the key the options set.
+func Read(path string, structPtr any, opts ...options.Option) error {
 	if errValidation := reflect.Validation(structPtr); errValidation != nil {
 		return fmt.Errorf("error validating struct: %w", errValidation)
 	}
@@ -30,28 +119,136 @@ func Read(path string, structPtr any) error {
 		return fmt.Errorf("error setting default values: %w", errDefault)
 	}
 
+	o := options.New(opts...)
+	o.Profile = resolveProfile(o)
+
+	err := readFile(path, structPtr, o, nil)
+	if err == nil && o.Profile != "" {
+		err = readProfileFile(path, o.Profile, structPtr, o)
+	}
+	if err == nil {
+		err = crypt.DecryptStruct(structPtr, o)
+	}
+
+	// The errors may quote the values of the file or the external variables of Jsonnet, so the
+	// values of the secrets known so far are redacted.
+	if err != nil {
+		return secret.RedactError(err, append(secret.EnvValues(structPtr, o.Naming), secret.Values(structPtr)...))
+	}
+
+	return nil
+}
+
+// readFile is a helper function used by Read to parse a single file. The chain parameter
+// holds the files that led to this one through includes. Included files are resolved
+// relative to the directory of the including file and are read recursively before the
+// content of the file itself. The function returns ErrIncludeCycle if the file is already
+// part of the chain.
+func readFile(path string, structPtr any, opts options.Options, chain []string) error {
+	if err := checkCycle(path, chain); err != nil {
+		return err
+	}
+
+	if opts.OnRead != nil {
+		opts.OnRead(path)
+	}
+
 	file, err := os.OpenFile(path, os.O_RDONLY|os.O_SYNC, 0)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	switch extension := strings.ToLower(filepath.Ext(path)); extension {
-	case ".json":
-		if err = parseJSON(file, structPtr); err != nil {
-			return fmt.Errorf("failed to parse json: %w", err)
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	f, ok := formats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil
+	}
+
+	if f.name == "json" && opts.LenientJSON {
+		f.parse, f.includes, f.nodes, f.profile = parseJSON5, includesJSON5, nodesJSON5, profileJSON5
+		f.document = documentJSON5
+	}
+
+	if f.evaluate != nil {
+		if data, err = f.evaluate(path, data, structPtr, opts); err != nil {
+			return fmt.Errorf("failed to evaluate %s: %w", f.name, err)
+		}
+	}
+
+	if f.includes != nil {
+		includes, errIncludes := f.includes(data)
+		if errIncludes != nil {
+			return fmt.Errorf("failed to parse %s includes of %s: %w", f.name, path, errIncludes)
+		}
+
+		chain = append(chain[:len(chain):len(chain)], path)
+		for _, include := range includes {
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(filepath.Dir(path), include)
+			}
+
+			if err = readFile(include, structPtr, opts, chain); err != nil {
+				var errInclude *includeError
+				if errors.As(err, &errInclude) || errors.Is(err, ErrIncludeCycle) {
+					return err
+				}
+				return &includeError{chain: append(chain[:len(chain):len(chain)], include), err: err}
+			}
+		}
+	}
+
+	if opts.Strict {
+		if err = checkStrict(path, f, data, structPtr, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.ValidateSchema {
+		if err = checkSchema(path, f, data, structPtr, opts); err != nil {
+			return err
 		}
-	case ".yaml", ".yml":
-		if err = parseYAML(file, structPtr); err != nil {
-			return fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	if err = f.parse(bytes.NewReader(data), structPtr, opts); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", f.name, err)
+	}
+
+	if opts.Profile != "" && f.profile != nil {
+		section, errProfile := f.profile(data, opts.Profile)
+		if errProfile != nil {
+			return fmt.Errorf("failed to parse %s profile %q: %w", f.name, opts.Profile, errProfile)
 		}
-	case ".toml":
-		if err = parseTOML(file, structPtr); err != nil {
-			return fmt.Errorf("failed to parse toml: %w", err)
+
+		if section != nil {
+			if err = f.parse(bytes.NewReader(section), structPtr, opts); err != nil {
+				return fmt.Errorf("failed to parse %s profile %q: %w", f.name, opts.Profile, err)
+			}
 		}
-	case ".env":
-		if err = parseENV(file, structPtr); err != nil {
-			return fmt.Errorf("failed to parse env: %w", err)
+	}
+
+	if opts.Track != nil {
+		return trackFields(path, f, data, structPtr, opts)
+	}
+
+	return nil
+}
+
+// checkCycle is a helper function used by readFile to detect include cycles. It returns
+// ErrIncludeCycle with the full chain if path already is one of the including files.
+func checkCycle(path string, chain []string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	for _, included := range chain {
+		if absIncluded, _ := filepath.Abs(included); absIncluded == absPath {
+			return fmt.Errorf("%w: %s -> %s", ErrIncludeCycle, strings.Join(chain, " -> "), path)
 		}
 	}
 
@@ -61,47 +258,150 @@ func Read(path string, structPtr any) error {
 // parseJSON is a helper function used by Read to parse the JSON content of the file.
 // It takes an io.Reader and a pointer to a struct where each field represents a
 // configuration option. The function returns an error if the parsing process fails.
-func parseJSON(r io.Reader, structPtr any) error {
-	return json.NewDecoder(r).Decode(structPtr)
+func parseJSON(r io.Reader, structPtr any, opts options.Options) error {
+	if !reflect.HasCanonicalKeys(rf.TypeOf(structPtr), opts.Naming) {
+		return json.NewDecoder(r).Decode(structPtr)
+	}
+
+	var document map[string]any
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(remap(document, structPtr, "json", opts.Naming))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, structPtr)
 }
 
 // parseYAML is a helper function used by Read to parse the YAML content of the file.
 // It takes an io.Reader and a pointer to a struct where each field represents a
 // configuration option. The function returns an error if the parsing process fails.
-func parseYAML(r io.Reader, structPtr any) error {
-	return yaml.NewDecoder(r).Decode(structPtr)
+func parseYAML(r io.Reader, structPtr any, opts options.Options) error {
+	if !reflect.HasCanonicalKeys(rf.TypeOf(structPtr), opts.Naming) {
+		return yaml.NewDecoder(r).Decode(structPtr)
+	}
+
+	var document map[string]any
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(remap(document, structPtr, "yaml", opts.Naming))
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(data, structPtr)
 }
 
 // parseTOML is a helper function used by Read to parse the TOML content of the file.
 // It takes an io.Reader and a pointer to a struct where each field represents a
 // configuration option. The function returns an error if the parsing process fails.
-func parseTOML(r io.Reader, structPtr any) error {
-	_, err := toml.NewDecoder(r).Decode(structPtr)
+func parseTOML(r io.Reader, structPtr any, opts options.Options) error {
+	if !reflect.HasCanonicalKeys(rf.TypeOf(structPtr), opts.Naming) {
+		_, err := toml.NewDecoder(r).Decode(structPtr)
+		return err
+	}
+
+	var document map[string]any
+	if _, err := toml.NewDecoder(r).Decode(&document); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(remap(document, structPtr, "toml", opts.Naming)); err != nil {
+		return err
+	}
+
+	_, err := toml.Decode(buf.String(), structPtr)
 	return err
 }
 
 // parseENV is a helper function used by Read to parse the ENV content of the file.
 // It takes an io.Reader and a pointer to a struct where each field represents a
 // configuration option. The function returns an error if the parsing process fails.
-func parseENV(r io.Reader, structPtr any) error {
+func parseENV(r io.Reader, structPtr any, opts options.Options) error {
 	dataEnv, err := godotenv.Parse(r)
 	if err != nil {
 		return fmt.Errorf("failed to parse env: %w", err)
 	}
 
-	parsedStruct, err := reflect.ParseTag(structPtr, "env")
-	if err != nil {
-		return fmt.Errorf("failed to parse tag: %w", err)
-	}
-
+	fieldKeys := reflect.FieldKeys(structPtr, opts.Naming)
 	if errReflection := reflect.WriteToStruct(structPtr, func(fieldName string) string {
-		if _, ok := parsedStruct[fieldName]; !ok {
+		if fieldKeys[fieldName].Env == "" {
 			return ""
 		}
-		return dataEnv[parsedStruct[fieldName].TagValue]
+		return dataEnv[fieldKeys[fieldName].Env]
 	}); errReflection != nil {
 		return errReflection
 	}
 
 	return nil
 }
+
+// includesJSON is a helper function used by Read to list the files included by the JSON
+// content. It returns an error if the content is not valid JSON.
+func includesJSON(data []byte) ([]string, error) {
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return includeList(document)
+}
+
+// includesYAML is a helper function used by Read to list the files included by the YAML
+// content. It returns an error if the content is not valid YAML.
+func includesYAML(data []byte) ([]string, error) {
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return includeList(document)
+}
+
+// includesTOML is a helper function used by Read to list the files included by the TOML
+// content. Since the dollar sign is not allowed in bare keys, the key must be quoted in
+// the file. It returns an error if the content is not a valid TOML document.
+func includesTOML(data []byte) ([]string, error) {
+	var document map[string]any
+	if _, err := toml.Decode(string(data), &document); err != nil {
+		return nil, err
+	}
+	return includeList(document)
+}
+
+// includeList is a helper function used by the includes functions that returns the files
+// listed in the $include key of the decoded document. Documents that are not mappings
+// include nothing. It returns an error if the key is not a list of paths.
+func includeList(document any) ([]string, error) {
+	var value any
+	switch doc := document.(type) {
+	case map[string]any:
+		value = doc[includeKey]
+	case map[any]any:
+		value = doc[includeKey]
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("the %s key must be a list of paths", includeKey)
+	}
+
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		path, isString := item.(string)
+		if !isString {
+			return nil, fmt.Errorf("the %s key must be a list of paths, got %v", includeKey, item)
+		}
+		result = append(result, path)
+	}
+	return result, nil
+}
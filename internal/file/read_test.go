@@ -8,15 +8,18 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/crypt"
+	"github.com/dsbasko/go-cfg/internal/options"
 )
 
 func Test_Read(t *testing.T) {
 	type InStructNested struct {
-		Field string `json:"field" yaml:"field" toml:"field" env:"NESTED_FIELD"`
+		Field string `json:"field" yaml:"field" toml:"field" hcl:"field" xml:"field" env:"NESTED_FIELD"`
 	}
 	type InStruct struct {
-		Field  string         `json:"field" yaml:"field" toml:"field" env:"FIELD"`
-		Nested InStructNested `json:"nested" yaml:"nested" toml:"nested"`
+		Field  string         `json:"field" yaml:"field" toml:"field" hcl:"field" xml:"field" env:"FIELD"`
+		Nested InStructNested `json:"nested" yaml:"nested" toml:"nested" hcl:"nested" xml:"nested"`
 	}
 
 	tests := []struct {
@@ -45,6 +48,56 @@ func Test_Read(t *testing.T) {
 			wantStruct: &InStruct{},
 			wantError:  true,
 		},
+		{
+			name:      "Happy Path JSON5",
+			path:      path.Join("tests", "cfg.json5"),
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "json5FieldValue",
+				Nested: InStructNested{
+					Field: "json5NestedFieldValue",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:      "Happy Path JSONC",
+			path:      path.Join("tests", "cfg.jsonc"),
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "jsoncFieldValue",
+				Nested: InStructNested{
+					Field: "jsoncNestedFieldValue",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:       "Broken JSONC",
+			path:       path.Join("tests", "broken.jsonc"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantError:  true,
+		},
+		{
+			name:      "Happy Path Jsonnet",
+			path:      path.Join("tests", "cfg.jsonnet"),
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "jsonnetFieldValue",
+				Nested: InStructNested{
+					Field: "jsonnetNestedFieldValue",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:       "Broken Jsonnet",
+			path:       path.Join("tests", "broken.jsonnet"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantError:  true,
+		},
 		{
 			name:      "Happy Path YAML",
 			path:      path.Join("tests", "cfg.yaml"),
@@ -83,6 +136,87 @@ func Test_Read(t *testing.T) {
 			wantStruct: &InStruct{},
 			wantError:  true,
 		},
+		{
+			name:      "Happy Path HCL",
+			path:      path.Join("tests", "cfg.hcl"),
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "hclFieldValue",
+				Nested: InStructNested{
+					Field: "hclNestedFieldValue",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:       "Broken HCL",
+			path:       path.Join("tests", "broken.hcl"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantError:  true,
+		},
+		{
+			name:      "Happy Path XML",
+			path:      path.Join("tests", "cfg.xml"),
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "xmlFieldValue",
+				Nested: InStructNested{
+					Field: "xmlNestedFieldValue",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:      "Happy Path XML Include",
+			path:      path.Join("tests", "include-xml.xml"),
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "includeFieldValue",
+				Nested: InStructNested{
+					Field: "xmlNestedFieldValue",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:       "Broken XML",
+			path:       path.Join("tests", "broken.xml"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantError:  true,
+		},
+		{
+			name:      "Happy Path INI",
+			path:      path.Join("tests", "cfg.ini"),
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "iniFieldValue",
+				Nested: InStructNested{
+					Field: "iniNestedFieldValue",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:       "Broken INI",
+			path:       path.Join("tests", "broken.ini"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantError:  true,
+		},
+		{
+			name:      "Happy Path Properties",
+			path:      path.Join("tests", "cfg.properties"),
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "propertiesFieldValue",
+				Nested: InStructNested{
+					Field: "propertiesNestedFieldValue",
+				},
+			},
+			wantError: false,
+		},
 		{
 			name:      "Happy Path ENV",
 			path:      path.Join("tests", "cfg.env"),
@@ -96,37 +230,508 @@ func Test_Read(t *testing.T) {
 			wantError: false,
 		},
 		{
-			name:       "Broken ENV",
-			path:       path.Join("tests", "broken.env"),
+			name:       "Broken ENV",
+			path:       path.Join("tests", "broken.env"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantError:  true,
+		},
+		{
+			name:      "Happy Path Include",
+			path:      path.Join("tests", "include.yaml"),
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "yamlIncludeFieldValue",
+				Nested: InStructNested{
+					Field: "jsonIncludeNestedFieldValue",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:       "Include Not Found",
+			path:       path.Join("tests", "include-missing.toml"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantError:  true,
+		},
+		{
+			name:       "Include Cycle",
+			path:       path.Join("tests", "include-cycle-a.yaml"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantError:  true,
+		},
+		{
+			name:       "Fail Path",
+			path:       path.Join("not", "found"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantError:  true,
+		},
+		{
+			name:       "Validation Not Pointer",
+			path:       path.Join("tests"),
+			structPtr:  InStruct{},
+			wantStruct: &InStruct{},
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Read(tt.path, tt.structPtr)
+			if err != nil {
+				if (err != nil) != tt.wantError {
+					t.Errorf("Read() error = %v, wantErr %v", err, tt.wantError)
+				}
+				return
+			}
+			assert.EqualValues(t, tt.wantStruct, tt.structPtr)
+		})
+	}
+}
+
+func Test_readFile_Include(t *testing.T) {
+	type InStruct struct {
+		Field string `json:"field" yaml:"field" toml:"field"`
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		wantErr   error
+		wantChain string
+	}{
+		{
+			name:      "Cycle",
+			path:      path.Join("tests", "include-cycle-a.yaml"),
+			wantErr:   ErrIncludeCycle,
+			wantChain: "tests/include-cycle-a.yaml -> tests/include-cycle-b.yaml -> tests/include-cycle-a.yaml",
+		},
+		{
+			name:      "Not Found",
+			path:      path.Join("tests", "include-missing.toml"),
+			wantErr:   os.ErrNotExist,
+			wantChain: "tests/include-missing.toml -> tests/not-found.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := readFile(tt.path, &InStruct{}, options.Options{}, nil)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), tt.wantChain)
+		})
+	}
+}
+
+func Test_includes(t *testing.T) {
+	tests := []struct {
+		name     string
+		includes func(data []byte) ([]string, error)
+		data     string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "YAML",
+			includes: includesYAML,
+			data:     "$include: [db.yaml, cache.toml]\nfield: value\n",
+			want:     []string{"db.yaml", "cache.toml"},
+		},
+		{
+			name:     "YAML Sequence Document",
+			includes: includesYAML,
+			data:     "- db.yaml\n- cache.toml\n",
+		},
+		{
+			name:     "YAML Not A List",
+			includes: includesYAML,
+			data:     "$include: db.yaml\n",
+			wantErr:  "the $include key must be a list of paths",
+		},
+		{
+			name:     "JSON",
+			includes: includesJSON,
+			data:     `{"$include": ["db.json"]}`,
+			want:     []string{"db.json"},
+		},
+		{
+			name:     "JSON Array Document",
+			includes: includesJSON,
+			data:     `["db.json"]`,
+		},
+		{
+			name:     "JSON Not A Path",
+			includes: includesJSON,
+			data:     `{"$include": [1]}`,
+			wantErr:  "the $include key must be a list of paths, got 1",
+		},
+		{
+			name:     "TOML",
+			includes: includesTOML,
+			data:     `"$include" = ["db.toml"]`,
+			want:     []string{"db.toml"},
+		},
+		{
+			name:     "HCL",
+			includes: includesHCL,
+			data:     `"$include" = ["db.hcl"]`,
+			want:     []string{"db.hcl"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.includes([]byte(tt.data))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Read_Strict(t *testing.T) {
+	type InStructNested struct {
+		Field string `json:"field" yaml:"field" toml:"field" hcl:"field" xml:"field" env:"NESTED_FIELD"`
+	}
+	type InStruct struct {
+		Field  string         `json:"field" yaml:"field" toml:"field" hcl:"field" xml:"field" env:"FIELD"`
+		Nested InStructNested `json:"nested" yaml:"nested" toml:"nested" hcl:"nested" xml:"nested"`
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{
+			name:    "Happy Path",
+			path:    path.Join("tests", "cfg.yaml"),
+			wantErr: "",
+		},
+		{
+			name:    "Happy Path Include",
+			path:    path.Join("tests", "include.yaml"),
+			wantErr: "",
+		},
+		{
+			name:    "Unknown JSON",
+			path:    path.Join("tests", "strict.json"),
+			wantErr: `tests/strict.json:3: "nestd" (did you mean "nested"?)`,
+		},
+		{
+			name:    "Unknown JSONC",
+			path:    path.Join("tests", "strict.jsonc"),
+			wantErr: `tests/strict.jsonc:5: "nested.feld" (did you mean "nested.field"?)`,
+		},
+		{
+			name:    "Unknown YAML Profile",
+			path:    path.Join("tests", "strict-profile.yaml"),
+			wantErr: `tests/strict-profile.yaml:7: "profiles.dev.nested.feild" (did you mean "profiles.dev.nested.field"?)`,
+		},
+		{
+			name:    "Unknown YAML",
+			path:    path.Join("tests", "strict.yaml"),
+			wantErr: `tests/strict.yaml:4: "nested.feild" (did you mean "nested.field"?)`,
+		},
+		{
+			name:    "Unknown TOML",
+			path:    path.Join("tests", "strict.toml"),
+			wantErr: `tests/strict.toml:5: "nested.fieldd" (did you mean "nested.field"?)`,
+		},
+		{
+			name:    "Unknown HCL",
+			path:    path.Join("tests", "strict.hcl"),
+			wantErr: `tests/strict.hcl:5: "nested.fild" (did you mean "nested.field"?)`,
+		},
+		{
+			name:    "Unknown XML",
+			path:    path.Join("tests", "strict.xml"),
+			wantErr: `tests/strict.xml:5: "nested.fields" (did you mean "nested.field"?)`,
+		},
+		{
+			name:    "Unknown INI",
+			path:    path.Join("tests", "strict.ini"),
+			wantErr: `tests/strict.ini:5: "nested.feld" (did you mean "nested.Field"?)`,
+		},
+		{
+			name:    "Unknown Properties",
+			path:    path.Join("tests", "strict.properties"),
+			wantErr: `tests/strict.properties:2: "nested.fields" (did you mean "nested.Field"?)`,
+		},
+		{
+			name:    "Unknown ENV",
+			path:    path.Join("tests", "strict.env"),
+			wantErr: `tests/strict.env:2: "NESTED_FEILD" (did you mean "NESTED_FIELD"?)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Read(tt.path, &InStruct{}, func(o *options.Options) { o.Strict = true })
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrUnknownKey)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func Test_Read_LenientJSON(t *testing.T) {
+	type InStruct struct {
+		Field string `json:"field"`
+	}
+
+	dir := t.TempDir()
+	filePath := path.Join(dir, "cfg.json")
+	err := os.WriteFile(filePath, []byte("{\n  // comment\n  field: 'value',\n}\n"), 0o600)
+	assert.NoError(t, err)
+
+	var strict InStruct
+	assert.Error(t, Read(filePath, &strict))
+
+	var lenient InStruct
+	assert.NoError(t, Read(filePath, &lenient, func(o *options.Options) { o.LenientJSON = true }))
+	assert.Equal(t, "value", lenient.Field)
+}
+
+func Test_Read_Profile(t *testing.T) {
+	type InStructNested struct {
+		Field string `json:"field" yaml:"field" toml:"field" hcl:"field" xml:"field"`
+	}
+	type InStruct struct {
+		Field  string         `json:"field" yaml:"field" toml:"field" hcl:"field" xml:"field"`
+		Nested InStructNested `json:"nested" yaml:"nested" toml:"nested" hcl:"nested" xml:"nested"`
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		opts       options.Options
+		env        map[string]string
+		args       []string
+		wantStruct *InStruct
+	}{
+		{
+			name:       "No Profile",
+			path:       path.Join("tests", "profile.yaml"),
+			wantStruct: &InStruct{Field: "yamlFieldValue", Nested: InStructNested{Field: "yamlNestedFieldValue"}},
+		},
+		{
+			name:       "Inline Section And Profile File",
+			path:       path.Join("tests", "profile.yaml"),
+			opts:       options.Options{Profile: "prod"},
+			wantStruct: &InStruct{Field: "prodFieldValue", Nested: InStructNested{Field: "prodFileNestedFieldValue"}},
+		},
+		{
+			name:       "Profile From Env",
+			path:       path.Join("tests", "profile.yaml"),
+			opts:       options.Options{ProfileEnv: "APP_PROFILE", ProfileFlag: "profile"},
+			env:        map[string]string{"APP_PROFILE": "dev"},
+			wantStruct: &InStruct{Field: "yamlFieldValue", Nested: InStructNested{Field: "devNestedFieldValue"}},
+		},
+		{
+			name:       "Profile From Flag",
+			path:       path.Join("tests", "profile.yaml"),
+			opts:       options.Options{ProfileEnv: "APP_PROFILE", ProfileFlag: "profile"},
+			env:        map[string]string{"APP_PROFILE": "dev"},
+			args:       []string{"--profile", "prod"},
+			wantStruct: &InStruct{Field: "prodFieldValue", Nested: InStructNested{Field: "prodFileNestedFieldValue"}},
+		},
+		{
+			name:       "Unknown Profile",
+			path:       path.Join("tests", "profile.yaml"),
+			opts:       options.Options{Profile: "staging"},
+			wantStruct: &InStruct{Field: "yamlFieldValue", Nested: InStructNested{Field: "yamlNestedFieldValue"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			args := os.Args
+			os.Args = append([]string{args[0]}, tt.args...)
+			defer func() { os.Args = args }()
+
+			structPtr := &InStruct{}
+			err := Read(tt.path, structPtr, func(o *options.Options) { *o = tt.opts })
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStruct, structPtr)
+		})
+	}
+}
+
+func Test_profile(t *testing.T) {
+	type InStructNested struct {
+		Field string `json:"field" yaml:"field" toml:"field" hcl:"field" xml:"field"`
+	}
+	type InStruct struct {
+		Field  string         `json:"field" yaml:"field" toml:"field" hcl:"field" xml:"field"`
+		Nested InStructNested `json:"nested" yaml:"nested" toml:"nested" hcl:"nested" xml:"nested"`
+	}
+
+	tests := []struct {
+		name string
+		ext  string
+		data string
+	}{
+		{
+			name: "JSON",
+			ext:  ".json",
+			data: `{"field": "base", "profiles": {"prod": {"nested": {"field": "prod"}}}}`,
+		},
+		{
+			name: "JSONC",
+			ext:  ".jsonc",
+			data: "{\n  \"field\": \"base\",\n  // prod\n  profiles: {prod: {nested: {field: 'prod'}}},\n}",
+		},
+		{
+			name: "YAML",
+			ext:  ".yaml",
+			data: "field: base\nprofiles:\n  prod:\n    nested:\n      field: prod\n",
+		},
+		{
+			name: "TOML",
+			ext:  ".toml",
+			data: "field = \"base\"\n\n[profiles.prod.nested]\nfield = \"prod\"\n",
+		},
+		{
+			name: "HCL",
+			ext:  ".hcl",
+			data: "field = \"base\"\n\nprofiles {\n  prod {\n    nested {\n      field = \"prod\"\n    }\n  }\n}\n",
+		},
+		{
+			name: "INI",
+			ext:  ".ini",
+			data: "field = base\n\n[profiles.prod.nested]\nfield = prod\n",
+		},
+		{
+			name: "Properties",
+			ext:  ".properties",
+			data: "field = base\nprofiles.prod.nested.field = prod\n",
+		},
+		{
+			name: "XML",
+			ext:  ".xml",
+			data: "<config><field>base</field><profiles><prod><nested field=\"prod\"/></prod></profiles></config>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := path.Join(t.TempDir(), "cfg"+tt.ext)
+			assert.NoError(t, os.WriteFile(filePath, []byte(tt.data), 0o600))
+
+			structPtr := &InStruct{}
+			err := Read(filePath, structPtr, func(o *options.Options) { o.Profile = "prod"; o.Strict = true })
+			assert.NoError(t, err)
+			assert.Equal(t, &InStruct{Field: "base", Nested: InStructNested{Field: "prod"}}, structPtr)
+		})
+	}
+}
+
+func Test_Read_Canonical(t *testing.T) {
+	type InStructNested struct {
+		Field string `cfg:"field"`
+	}
+	type InStruct struct {
+		Field  string         `cfg:"field"`
+		Nested InStructNested `cfg:"nested"`
+	}
+	type InStructDotted struct {
+		Field       string `cfg:"field"`
+		NestedField string `cfg:"nested.field" env:"NESTED_FIELD"`
+	}
+	type InStructNamingNested struct {
+		Field string
+	}
+	type InStructNaming struct {
+		Field  string
+		Nested InStructNamingNested
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		structPtr  any
+		naming     func(string) string
+		wantStruct any
+	}{
+		{
+			name:       "JSON",
+			path:       path.Join("tests", "cfg.json"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{Field: "jsonFieldValue", Nested: InStructNested{Field: "jsonNestedFieldValue"}},
+		},
+		{
+			name:       "YAML",
+			path:       path.Join("tests", "cfg.yaml"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{Field: "yamlFieldValue", Nested: InStructNested{Field: "yamlNestedFieldValue"}},
+		},
+		{
+			name:       "TOML",
+			path:       path.Join("tests", "cfg.toml"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{Field: "tomlFieldValue", Nested: InStructNested{Field: "tomlNestedFieldValue"}},
+		},
+		{
+			name:       "HCL",
+			path:       path.Join("tests", "cfg.hcl"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{Field: "hclFieldValue", Nested: InStructNested{Field: "hclNestedFieldValue"}},
+		},
+		{
+			name:       "INI",
+			path:       path.Join("tests", "cfg.ini"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{Field: "iniFieldValue", Nested: InStructNested{Field: "iniNestedFieldValue"}},
+		},
+		{
+			name:       "XML",
+			path:       path.Join("tests", "cfg.xml"),
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{Field: "xmlFieldValue", Nested: InStructNested{Field: "xmlNestedFieldValue"}},
+		},
+		{
+			name:       "ENV",
+			path:       path.Join("tests", "cfg.env"),
 			structPtr:  &InStruct{},
-			wantStruct: &InStruct{},
-			wantError:  true,
+			wantStruct: &InStruct{Field: "envFieldValue", Nested: InStructNested{Field: "envNestedFieldValue"}},
 		},
 		{
-			name:       "Fail Path",
-			path:       path.Join("not", "found"),
-			structPtr:  &InStruct{},
-			wantStruct: &InStruct{},
-			wantError:  true,
+			name:       "Dotted Key",
+			path:       path.Join("tests", "cfg.yaml"),
+			structPtr:  &InStructDotted{},
+			wantStruct: &InStructDotted{Field: "yamlFieldValue", NestedField: "yamlNestedFieldValue"},
 		},
 		{
-			name:       "Validation Not Pointer",
-			path:       path.Join("tests"),
-			structPtr:  InStruct{},
-			wantStruct: &InStruct{},
-			wantError:  true,
+			name:       "Naming",
+			path:       path.Join("tests", "cfg.toml"),
+			structPtr:  &InStructNaming{},
+			naming:     strings.ToLower,
+			wantStruct: &InStructNaming{Field: "tomlFieldValue", Nested: InStructNamingNested{Field: "tomlNestedFieldValue"}},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := Read(tt.path, tt.structPtr)
-			if err != nil {
-				if (err != nil) != tt.wantError {
-					t.Errorf("Read() error = %v, wantErr %v", err, tt.wantError)
-				}
-				return
-			}
+			err := Read(tt.path, tt.structPtr, func(o *options.Options) {
+				o.Strict = true
+				o.Naming = tt.naming
+			})
+			assert.NoError(t, err)
 			assert.EqualValues(t, tt.wantStruct, tt.structPtr)
 		})
 	}
@@ -177,7 +782,7 @@ func Test_parseJSON(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := parseJSON(tt.reader(), tt.structPtr); (err != nil) != tt.wantErr {
+			if err := parseJSON(tt.reader(), tt.structPtr, options.Options{}); (err != nil) != tt.wantErr {
 				t.Errorf("parseJSON() error = %v, wantErr %v", err, tt.wantErr)
 			} else {
 				assert.EqualValues(t, tt.wantStruct, tt.structPtr)
@@ -230,7 +835,7 @@ func Test_parseYAML(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := parseYAML(tt.reader(), tt.structPtr); (err != nil) != tt.wantErr {
+			if err := parseYAML(tt.reader(), tt.structPtr, options.Options{}); (err != nil) != tt.wantErr {
 				t.Errorf("parseYAML() error = %v, wantErr %v", err, tt.wantErr)
 			} else {
 				assert.EqualValues(t, tt.wantStruct, tt.structPtr)
@@ -283,7 +888,7 @@ func Test_parseTOML(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := parseTOML(tt.reader(), tt.structPtr); (err != nil) != tt.wantErr {
+			if err := parseTOML(tt.reader(), tt.structPtr, options.Options{}); (err != nil) != tt.wantErr {
 				t.Errorf("parseTOML() error = %v, wantErr %v", err, tt.wantErr)
 			} else {
 				assert.EqualValues(t, tt.wantStruct, tt.structPtr)
@@ -292,6 +897,320 @@ func Test_parseTOML(t *testing.T) {
 	}
 }
 
+func Test_parseHCL(t *testing.T) {
+	type InStructNested struct {
+		Field string `hcl:"field"`
+	}
+	type InStruct struct {
+		Field  string         `hcl:"field"`
+		Nested InStructNested `hcl:"nested"`
+	}
+
+	tests := []struct {
+		name       string
+		reader     func() io.Reader
+		structPtr  any
+		wantStruct *InStruct
+		wantErr    bool
+	}{
+		{
+			name: "Happy Path",
+			reader: func() io.Reader {
+				file, err := os.Open(path.Join("tests", "cfg.hcl"))
+				assert.NoError(t, err)
+				return file
+			},
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "hclFieldValue",
+				Nested: InStructNested{
+					Field: "hclNestedFieldValue",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Parse Error",
+			reader: func() io.Reader {
+				return strings.NewReader(`not hcl`)
+			},
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantErr:    true,
+		},
+		{
+			name: "HCL 2 Expression",
+			reader: func() io.Reader {
+				return strings.NewReader("field = upper(\"value\")\n")
+			},
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := parseHCL(tt.reader(), tt.structPtr, options.Options{}); (err != nil) != tt.wantErr {
+				t.Errorf("parseHCL() error = %v, wantErr %v", err, tt.wantErr)
+			} else {
+				assert.EqualValues(t, tt.wantStruct, tt.structPtr)
+			}
+		})
+	}
+}
+
+func Test_parseXML(t *testing.T) {
+	type InStructNested struct {
+		Host string `xml:"host,attr"`
+		Port int    `xml:"port"`
+	}
+	type InStruct struct {
+		Field  string         `xml:"field"`
+		Debug  bool           `xml:"debug"`
+		Nested InStructNested `xml:"nested"`
+	}
+
+	tests := []struct {
+		name       string
+		reader     func() io.Reader
+		structPtr  any
+		wantStruct *InStruct
+		wantErr    bool
+	}{
+		{
+			name: "Happy Path",
+			reader: func() io.Reader {
+				return strings.NewReader(`<config debug="true"><field> xmlFieldValue </field><nested host="localhost"><port>8080</port></nested></config>`)
+			},
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "xmlFieldValue",
+				Debug: true,
+				Nested: InStructNested{
+					Host: "localhost",
+					Port: 8080,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Overlay",
+			reader: func() io.Reader {
+				return strings.NewReader(`<config><nested><port>8080</port></nested><field/></config>`)
+			},
+			structPtr: &InStruct{Field: "default", Nested: InStructNested{Host: "0.0.0.0"}},
+			wantStruct: &InStruct{
+				Field: "default",
+				Nested: InStructNested{
+					Host: "0.0.0.0",
+					Port: 8080,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Parse Error",
+			reader: func() io.Reader {
+				return strings.NewReader(`<config><field></config>`)
+			},
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := parseXML(tt.reader(), tt.structPtr, options.Options{}); (err != nil) != tt.wantErr {
+				t.Errorf("parseXML() error = %v, wantErr %v", err, tt.wantErr)
+			} else {
+				assert.EqualValues(t, tt.wantStruct, tt.structPtr)
+			}
+		})
+	}
+}
+
+func Test_entriesXML(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []entry
+	}{
+		{
+			name: "Elements",
+			data: "<config>\n  <mode>prod</mode>\n  <http>\n    <port>8080</port>\n  </http>\n</config>\n",
+			want: []entry{
+				{key: "mode", value: "prod", line: 2},
+				{key: "http.port", value: "8080", line: 4},
+			},
+		},
+		{
+			name: "Multi-Line Start Tag",
+			data: "<config>\n  <http\n    host=\"localhost\"\n    port=\"8080\">\n    <path>/</path>\n  </http>\n</config>\n",
+			want: []entry{
+				{key: "http.host", value: "localhost", line: 3},
+				{key: "http.port", value: "8080", line: 4},
+				{key: "http.path", value: "/", line: 5},
+			},
+		},
+		{
+			name: "Attribute Values With Line Breaks",
+			data: "<config xmlns:x=\"urn:x\">\n  <http x:note='a > b\n c' host = \"localhost\"\n    port=\"8080\">\n    <path>/</path>\n  </http>\n</config>\n",
+			want: []entry{
+				{key: "http.note", value: "a > b\n c", line: 2},
+				{key: "http.host", value: "localhost", line: 3},
+				{key: "http.port", value: "8080", line: 4},
+				{key: "http.path", value: "/", line: 5},
+			},
+		},
+		{
+			name: "Multi-Line Element",
+			data: "<config>\n  <mode\n  >prod</mode>\n</config>\n",
+			want: []entry{
+				{key: "mode", value: "prod", line: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := entriesXML([]byte(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseINI(t *testing.T) {
+	type InStructNested struct {
+		Field string `ini:"field"`
+		Int   int    `ini:"int"`
+	}
+	type InStruct struct {
+		Field  string         `ini:"field"`
+		Nested InStructNested `ini:"nested"`
+	}
+
+	tests := []struct {
+		name       string
+		reader     func() io.Reader
+		structPtr  any
+		wantStruct *InStruct
+		wantErr    bool
+	}{
+		{
+			name: "Happy Path",
+			reader: func() io.Reader {
+				return strings.NewReader("# comment\nfield = 'iniFieldValue'\n\n[Nested]\nfield: iniNestedFieldValue\nint = 42\n")
+			},
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "iniFieldValue",
+				Nested: InStructNested{
+					Field: "iniNestedFieldValue",
+					Int:   42,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Parse Error",
+			reader: func() io.Reader {
+				return strings.NewReader("[nested\nfield = value")
+			},
+			structPtr:  &InStruct{},
+			wantStruct: &InStruct{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := parseINI(tt.reader(), tt.structPtr, options.Options{}); (err != nil) != tt.wantErr {
+				t.Errorf("parseINI() error = %v, wantErr %v", err, tt.wantErr)
+			} else {
+				assert.EqualValues(t, tt.wantStruct, tt.structPtr)
+			}
+		})
+	}
+}
+
+func Test_parseProperties(t *testing.T) {
+	type InStructNested struct {
+		Field string `properties:"field"`
+		Bool  bool   `properties:"bool"`
+	}
+	type InStruct struct {
+		Field  string         `properties:"field"`
+		Nested InStructNested `properties:"nested"`
+	}
+
+	tests := []struct {
+		name       string
+		reader     func() io.Reader
+		structPtr  any
+		wantStruct *InStruct
+	}{
+		{
+			name: "Happy Path",
+			reader: func() io.Reader {
+				file, err := os.Open(path.Join("tests", "cfg.properties"))
+				assert.NoError(t, err)
+				return file
+			},
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "propertiesFieldValue",
+				Nested: InStructNested{
+					Field: "propertiesNestedFieldValue",
+				},
+			},
+		},
+		{
+			name: "Separators And Escapes",
+			reader: func() io.Reader {
+				return strings.NewReader("! comment\nfield   tab\\tvalue \\u0041\nnested.bool:true\n")
+			},
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "tab\tvalue A",
+				Nested: InStructNested{
+					Bool: true,
+				},
+			},
+		},
+		{
+			name: "Continuation Lines",
+			reader: func() io.Reader {
+				return strings.NewReader("field = first \\\n    second\nnested.field = C:\\\\\nnested.bool = true\n")
+			},
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: "first second",
+				Nested: InStructNested{
+					Field: `C:\`,
+					Bool:  true,
+				},
+			},
+		},
+		{
+			name: "Escaped Backslash Before Continuation",
+			reader: func() io.Reader {
+				return strings.NewReader("field = a\\\\\\\n    b\n")
+			},
+			structPtr: &InStruct{},
+			wantStruct: &InStruct{
+				Field: `a\b`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseProperties(tt.reader(), tt.structPtr, options.Options{})
+			assert.NoError(t, err)
+			assert.EqualValues(t, tt.wantStruct, tt.structPtr)
+		})
+	}
+}
+
 func Test_parseENV(t *testing.T) {
 	type InStructNested struct {
 		Field string `env:"NESTED_FIELD"`
@@ -336,7 +1255,7 @@ func Test_parseENV(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := parseENV(tt.reader(), tt.structPtr); (err != nil) != tt.wantErr {
+			if err := parseENV(tt.reader(), tt.structPtr, options.Options{}); (err != nil) != tt.wantErr {
 				t.Errorf("parseTOML() error = %v, wantErr %v", err, tt.wantErr)
 			} else {
 				assert.EqualValues(t, tt.wantStruct, tt.structPtr)
@@ -344,3 +1263,42 @@ func Test_parseENV(t *testing.T) {
 		})
 	}
 }
+
+func Test_Read_Encrypted(t *testing.T) {
+	type InStructNested struct {
+		Password string `json:"password" yaml:"password" toml:"password" env:"NESTED_PASSWORD"`
+	}
+	type InStruct struct {
+		Field  string         `json:"field" yaml:"field" toml:"field" env:"FIELD"`
+		Nested InStructNested `json:"nested" yaml:"nested" toml:"nested"`
+	}
+
+	key, err := crypt.GenerateKey()
+	assert.NoError(t, err)
+	value, err := crypt.Encrypt(key, "hunter2")
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"cfg.json": `{"field": "plain", "nested": {"password": "` + value + `"}}`,
+		"cfg.yaml": "field: plain\nnested:\n  password: " + value + "\n",
+		"cfg.toml": "field = \"plain\"\n[nested]\npassword = \"" + value + "\"\n",
+		"cfg.env":  "FIELD=plain\nNESTED_PASSWORD=" + value + "\n",
+	}
+
+	for name, data := range files {
+		t.Run(name, func(t *testing.T) {
+			filePath := path.Join(dir, name)
+			assert.NoError(t, os.WriteFile(filePath, []byte(data), 0o600))
+
+			structPtr := &InStruct{}
+			errRead := Read(filePath, structPtr, func(o *options.Options) { o.EncryptionKey = key })
+			assert.NoError(t, errRead)
+			assert.Equal(t, &InStruct{Field: "plain", Nested: InStructNested{Password: "hunter2"}}, structPtr)
+
+			t.Setenv(crypt.DefaultKeyEnv, "")
+			errRead = Read(filePath, &InStruct{})
+			assert.ErrorIs(t, errRead, crypt.ErrNoKey)
+		})
+	}
+}
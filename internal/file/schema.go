@@ -0,0 +1,172 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	rf "reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/schema"
+)
+
+// violation is a value of a configuration file that does not match the schema.
+type violation struct {
+	key     string // The dotted path of the key, e.g. http.port.
+	line    int    // The line of the key, starting at 1. Zero if unknown.
+	message string // What is wrong with the value.
+}
+
+// checkSchema is a helper function used by Read to validate the values of the file against
+// the schema of the struct before the file is decoded, so the errors name the keys and their
+// lines instead of the messages of the decoders. The inline sections of all the profiles are
+// validated as well. It returns ErrSchemaViolation listing every violation in the order of
+// the lines. Formats that cannot be decoded into plain values are not validated.
+func checkSchema(path string, f format, data []byte, structPtr any, opts options.Options) error {
+	if f.document == nil {
+		return nil
+	}
+
+	s, err := schema.New(rf.TypeOf(structPtr), f.tag, opts.Naming)
+	if err != nil {
+		return fmt.Errorf("failed to generate schema: %w", err)
+	}
+
+	document, err := f.document(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", f.name, err)
+	}
+
+	violations := s.Validate(document, f.tag != "yaml")
+	if _, ok := s.Properties.Get(profilesKey); !ok {
+		if root, isMap := document.(map[string]any); isMap {
+			if profiles, isProfiles := root[profilesKey].(map[string]any); isProfiles {
+				violations = append(violations, validateProfiles(s, profiles, f.tag != "yaml")...)
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	nodes, err := f.nodes(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", f.name, err)
+	}
+
+	lines := map[string]int{}
+	nodeLines(nodes, "", lines)
+
+	result := make([]violation, len(violations))
+	for i, v := range violations {
+		result[i] = violation{key: v.Key(), line: violationLine(v.Path, lines), message: v.Message}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].line < result[j].line })
+
+	details := make([]string, len(result))
+	for i, v := range result {
+		location := path
+		if v.line > 0 && f.evaluate == nil {
+			location = fmt.Sprintf("%s:%d", path, v.line)
+		}
+		details[i] = fmt.Sprintf("%s: %s (%s)", v.key, v.message, location)
+	}
+
+	return fmt.Errorf("%w: %s", ErrSchemaViolation, strings.Join(details, ", "))
+}
+
+// validateProfiles is a helper function used by checkSchema to validate the inline sections
+// of the profiles against the schema of the whole file.
+func validateProfiles(s *schema.Schema, profiles map[string]any, fold bool) []schema.Violation {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []schema.Violation
+	for _, name := range names {
+		for _, v := range s.Validate(profiles[name], fold) {
+			v.Path = append([]string{profilesKey, name}, v.Path...)
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// nodeLines is a helper function used by checkSchema that maps the dotted paths of the keys
+// of the nodes to their lines.
+func nodeLines(nodes []node, prefix string, result map[string]int) {
+	for _, n := range nodes {
+		key := prefix + n.key
+		result[key] = n.line
+		nodeLines(n.children, key+".", result)
+	}
+}
+
+// violationLine is a helper function used by checkSchema that returns the line of the key
+// of the violation, or of its closest parent with a known line, e.g. the key of the array
+// for one of its elements.
+func violationLine(path []string, lines map[string]int) int {
+	var keys []string
+	for _, key := range path {
+		if strings.HasPrefix(key, "[") {
+			break
+		}
+		keys = append(keys, key)
+	}
+
+	for ; len(keys) > 0; keys = keys[:len(keys)-1] {
+		if line, ok := lines[strings.Join(keys, ".")]; ok {
+			return line
+		}
+	}
+	return 0
+}
+
+// documentJSON is a helper function used by checkSchema to decode the JSON content into
+// plain values, keeping the numbers as they are written.
+func documentJSON(data []byte) (any, error) {
+	var document any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// documentJSON5 is a helper function used by checkSchema to decode the JSON5 or JSONC
+// content into plain values.
+func documentJSON5(data []byte) (any, error) {
+	data, err := standardizeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return documentJSON(data)
+}
+
+// documentYAML is a helper function used by checkSchema to decode the YAML content into
+// plain values.
+func documentYAML(data []byte) (any, error) {
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// documentTOML is a helper function used by checkSchema to decode the TOML content into
+// plain values.
+func documentTOML(data []byte) (any, error) {
+	var document map[string]any
+	if _, err := toml.Decode(string(data), &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
@@ -0,0 +1,97 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+func Test_Read_Schema(t *testing.T) {
+	type InStructNested struct {
+		Port int `json:"port" yaml:"port" toml:"port" validate:"min=1,max=65535"`
+	}
+	type InStruct struct {
+		Mode     string         `json:"mode" yaml:"mode" toml:"mode" validate:"oneof=dev prod"`
+		Tags     []string       `json:"tags" yaml:"tags" toml:"tags" validate:"max=2"`
+		HTTP     InStructNested `json:"http" yaml:"http" toml:"http"`
+		Password secret.Secret  `json:"password" yaml:"password" toml:"password"`
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			file: "cfg.yaml",
+			data: "mode: dev\ntags: [a]\nhttp:\n  port: 8080\n",
+		},
+		{
+			name:    "YAML Type",
+			file:    "cfg.yaml",
+			data:    "mode: dev\nhttp:\n  port: 80a0\n",
+			want:    `schema violation: http.port: expected integer, got string "80a0" (%s:3)`,
+			wantErr: true,
+		},
+		{
+			name:    "YAML Several",
+			file:    "cfg.yaml",
+			data:    "http:\n  port: 0\ntags: [a, b, 3]\nmode: test\n",
+			want:    `schema violation: http.port: must be at least 1, got integer 0 (%[1]s:2), tags: number of items must be at most 2, got 3 (%[1]s:3), tags[2]: expected string, got integer 3 (%[1]s:3), mode: must be one of dev, prod, got string "test" (%[1]s:4)`,
+			wantErr: true,
+		},
+		{
+			name:    "YAML Profile",
+			file:    "cfg.yaml",
+			data:    "mode: dev\nprofiles:\n  prod:\n    mode: production\n",
+			want:    `schema violation: profiles.prod.mode: must be one of dev, prod, got string "production" (%s:4)`,
+			wantErr: true,
+		},
+		{
+			name:    "JSON",
+			file:    "cfg.json",
+			data:    "{\n  \"HTTP\": {\n    \"Port\": 1.5\n  }\n}\n",
+			want:    `schema violation: HTTP.Port: expected integer, got number 1.5 (%s:3)`,
+			wantErr: true,
+		},
+		{
+			name:    "TOML",
+			file:    "cfg.toml",
+			data:    "mode = \"dev\"\n\n[http]\nport = \"8080\"\n",
+			want:    `schema violation: http.port: expected integer, got string "8080" (%s:4)`,
+			wantErr: true,
+		},
+		{
+			name:    "Secret",
+			file:    "cfg.yaml",
+			data:    "password: 12345\n",
+			want:    `schema violation: password: expected string, got integer (%s:1)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := path.Join(t.TempDir(), tt.file)
+			assert.NoError(t, os.WriteFile(filePath, []byte(tt.data), 0o600))
+
+			err := Read(filePath, &InStruct{}, func(o *options.Options) { o.ValidateSchema = true })
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrSchemaViolation)
+			assert.EqualError(t, err, fmt.Sprintf(tt.want, filePath))
+		})
+	}
+}
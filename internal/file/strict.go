@@ -0,0 +1,549 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	rf "reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// node represents a key of a configuration file together with the line it is defined on.
+type node struct {
+	key      string // The key as written in the file.
+	line     int    // The line of the key, starting at 1. Zero if unknown.
+	value    string // The raw value of the key, without the quotes of a string. Empty for objects.
+	children []node // The keys nested under the key.
+}
+
+// unknownKey represents a key of a configuration file that does not match any field.
+type unknownKey struct {
+	path       string // The dotted path of the key.
+	line       int    // The line of the key, starting at 1. Zero if unknown.
+	suggestion string // The dotted path of the closest known key, if any.
+}
+
+// keyTree represents the keys a configuration file may contain for a struct.
+type keyTree struct {
+	children map[string]*keyTree // The keys nested under the key.
+	open     bool                // Any nested keys are accepted, as for maps and scalar values.
+}
+
+// checkStrict is a helper function used by Read to reject the keys of the file that do
+// not match any field of the struct. It returns ErrUnknownKey listing every unknown key
+// with its line and the closest known key.
+func checkStrict(path string, f format, data []byte, structPtr any, opts options.Options) error {
+	nodes, err := f.nodes(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", f.name, err)
+	}
+
+	var unknown []unknownKey
+	if f.tag == "env" {
+		unknown = unknownFlatKeys(nodes, structPtr, opts.Naming)
+	} else {
+		unknown = unknownKeys(nodes, newKeyTree(rf.TypeOf(structPtr).Elem(), f.tag, opts.Naming), f.tag, "")
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	details := make([]string, len(unknown))
+	for i, key := range unknown {
+		location := path
+		if key.line > 0 {
+			location = fmt.Sprintf("%s:%d", path, key.line)
+		}
+
+		details[i] = fmt.Sprintf("%s: %q", location, key.path)
+		if key.suggestion != "" {
+			details[i] += fmt.Sprintf(" (did you mean %q?)", key.suggestion)
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnknownKey, strings.Join(details, ", "))
+}
+
+// unknownKeys is a helper function used by checkStrict to walk the nodes alongside the
+// tree of the known keys. It returns the nodes whose keys are not in the tree.
+func unknownKeys(nodes []node, tree *keyTree, tag, prefix string) []unknownKey {
+	var result []unknownKey
+	for _, n := range nodes {
+		if prefix == "" && n.key == includeKey {
+			continue
+		}
+
+		child, ok := tree.lookup(n.key, tag)
+		if !ok && prefix == "" && n.key == profilesKey {
+			for _, profile := range n.children {
+				result = append(result, unknownKeys(profile.children, tree, tag, profilesKey+"."+profile.key+".")...)
+			}
+			continue
+		}
+		if !ok {
+			known := make([]string, 0, len(tree.children))
+			for key := range tree.children {
+				known = append(known, key)
+			}
+
+			unknown := unknownKey{path: prefix + n.key, line: n.line}
+			if suggestion := closest(n.key, known); suggestion != "" {
+				unknown.suggestion = prefix + suggestion
+			}
+			result = append(result, unknown)
+			continue
+		}
+
+		if !child.open {
+			result = append(result, unknownKeys(n.children, child, tag, prefix+n.key+".")...)
+		}
+	}
+
+	return result
+}
+
+// unknownFlatKeys is a helper function used by checkStrict for .env files, where every
+// field of the struct and its nested structs is mapped to a single environment variable.
+func unknownFlatKeys(nodes []node, structPtr any, naming func(string) string) []unknownKey {
+	var known []string
+	knownSet := map[string]struct{}{}
+	for _, keys := range reflect.FieldKeys(structPtr, naming) {
+		if keys.Env != "" {
+			known = append(known, keys.Env)
+			knownSet[keys.Env] = struct{}{}
+		}
+	}
+
+	var result []unknownKey
+	for _, n := range nodes {
+		if _, ok := knownSet[n.key]; !ok {
+			result = append(result, unknownKey{path: n.key, line: n.line, suggestion: closest(n.key, known)})
+		}
+	}
+
+	return result
+}
+
+// newKeyTree is a helper function that builds the tree of the keys a configuration file
+// may contain for the struct type, where the format maps keys to fields with the tag.
+func newKeyTree(typeOf rf.Type, tag string, naming func(string) string) *keyTree {
+	tree := &keyTree{children: map[string]*keyTree{}}
+	tree.add(typeOf, tag, naming)
+	return tree
+}
+
+// add is a helper method used by newKeyTree to add the keys of the exported fields of the
+// struct type to the tree. Embedded structs without a key are flattened into the parent.
+func (t *keyTree) add(typeOf rf.Type, tag string, naming func(string) string) {
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if field.Tag.Get(tag) == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == rf.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && fieldType.Kind() == rf.Struct && reflect.Segment(field, nil) == "" && field.Tag.Get(tag) == "" {
+			t.add(fieldType, tag, naming)
+			continue
+		}
+
+		current := t
+		for _, key := range reflect.FileKey(field, tag, naming) {
+			child, ok := current.children[key]
+			if !ok {
+				child = &keyTree{children: map[string]*keyTree{}}
+				current.children[key] = child
+			}
+			current = child
+		}
+
+		if fieldType.Kind() == rf.Struct {
+			current.add(fieldType, tag, naming)
+		} else {
+			current.open = true
+		}
+	}
+}
+
+// lookup is a helper method that finds the nested key in the tree. YAML keys must match
+// exactly, while JSON and TOML keys also match case-insensitively like their decoders do.
+func (t *keyTree) lookup(key, tag string) (*keyTree, bool) {
+	if child, ok := t.children[key]; ok {
+		return child, true
+	}
+
+	if tag != "yaml" {
+		for name, child := range t.children {
+			if strings.EqualFold(name, key) {
+				return child, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// closest is a helper function that returns the known key closest to the key, or an
+// empty string if none of them is close enough to be a likely misspelling.
+func closest(key string, known []string) string {
+	sort.Strings(known)
+
+	var result string
+	bestDistance := len(key)/3 + 2
+	for _, candidate := range known {
+		if distance := levenshtein(strings.ToLower(key), strings.ToLower(candidate)); distance < bestDistance {
+			result, bestDistance = candidate, distance
+		}
+	}
+
+	return result
+}
+
+// levenshtein is a helper function that returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(runesB)]
+}
+
+// min3 is a helper function that returns the smallest of three integers.
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// nodesJSON is a helper function used by checkStrict to list the keys of the JSON
+// content. It returns no keys if the content is not a JSON object.
+func nodesJSON(data []byte) ([]node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if token != json.Delim('{') {
+		return nil, nil
+	}
+
+	return walkJSONObject(decoder, data)
+}
+
+// walkJSONObject is a helper function used by nodesJSON to list the keys of the object
+// whose opening brace has just been read from the decoder.
+func walkJSONObject(decoder *json.Decoder, data []byte) ([]node, error) {
+	var result []node
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, _ := token.(string)
+		start := decoder.InputOffset()
+		line := lineAt(data, start)
+
+		children, err := walkJSONValue(decoder, data)
+		if err != nil {
+			return nil, err
+		}
+
+		n := node{key: key, line: line, children: children}
+		if children == nil {
+			n.value = rawJSON(data[start:decoder.InputOffset()])
+		}
+		result = append(result, n)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// walkJSONValue is a helper function used by nodesJSON to read the next value from the
+// decoder. It returns the keys of the value if it is an object.
+func walkJSONValue(decoder *json.Decoder, data []byte) ([]node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		return walkJSONObject(decoder, data)
+	case json.Delim('['):
+		for decoder.More() {
+			if _, err = walkJSONValue(decoder, data); err != nil {
+				return nil, err
+			}
+		}
+		_, err = decoder.Token()
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// rawJSON is a helper function used by walkJSONObject that returns the raw value that
+// follows a key, without the colon and the quotes of a string.
+func rawJSON(data []byte) string {
+	data = bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(data), []byte(":")))
+
+	var text string
+	if len(data) > 0 && data[0] == '"' && json.Unmarshal(data, &text) == nil {
+		return text
+	}
+	return string(data)
+}
+
+// lineAt is a helper function that returns the line of the offset in the data,
+// starting at 1.
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// nodesYAML is a helper function used by checkStrict to list the keys of the YAML
+// content. It returns no keys if the content is not a YAML mapping.
+func nodesYAML(data []byte) ([]node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	return walkYAML(document.Content[0]), nil
+}
+
+// walkYAML is a helper function used by nodesYAML to list the keys of the mapping node.
+// Merge keys are skipped, since they do not name a field.
+func walkYAML(mapping *yaml.Node) []node {
+	if mapping.Kind == yaml.AliasNode {
+		mapping = mapping.Alias
+	}
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var result []node
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if key.Tag == "!!merge" {
+			continue
+		}
+		result = append(result, node{key: key.Value, line: key.Line, value: rawYAML(value), children: walkYAML(value)})
+	}
+
+	return result
+}
+
+// rawYAML is a helper function used by walkYAML that returns the raw value of the node:
+// the text of a scalar, or the values of a sequence in the flow style. It returns an
+// empty string for mappings.
+func rawYAML(value *yaml.Node) string {
+	if value.Kind == yaml.AliasNode {
+		value = value.Alias
+	}
+
+	switch value.Kind {
+	case yaml.ScalarNode:
+		return value.Value
+	case yaml.SequenceNode:
+		flow := *value
+		flow.Style = yaml.FlowStyle
+		data, err := yaml.Marshal(&flow)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	default:
+		return ""
+	}
+}
+
+// nodesTOML is a helper function used by checkStrict to list the keys of the TOML
+// content in the order they are defined in.
+func nodesTOML(data []byte) ([]node, error) {
+	var document map[string]any
+	meta, err := toml.Decode(string(data), &document)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := linesTOML(data)
+	values := map[string]string{}
+	valuesTOML(document, "", values)
+
+	var result []node
+	for _, key := range meta.Keys() {
+		result = insertNode(result, key, lines, values, "")
+	}
+
+	return result, nil
+}
+
+// valuesTOML is a helper function used by nodesTOML that collects the values of the
+// decoded TOML document by the dotted paths of their keys. Strings are written without
+// quotes, and arrays in the TOML notation.
+func valuesTOML(document map[string]any, prefix string, result map[string]string) {
+	for key, value := range document {
+		if table, ok := value.(map[string]any); ok {
+			valuesTOML(table, prefix+key+".", result)
+			continue
+		}
+
+		if text, ok := value.(string); ok {
+			result[prefix+key] = text
+			continue
+		}
+
+		var buf bytes.Buffer
+		err := toml.NewEncoder(&buf).Encode(map[string]any{"v": value})
+		if encoded := buf.String(); err == nil && strings.HasPrefix(encoded, "v = ") {
+			result[prefix+key] = strings.TrimSpace(strings.TrimPrefix(encoded, "v = "))
+		}
+	}
+}
+
+// insertNode is a helper function used by nodesTOML to add the key path to the nodes,
+// creating the parent keys when needed. The values are keyed by the dotted paths.
+func insertNode(nodes []node, path []string, lines map[string]int, values map[string]string, prefix string) []node {
+	if len(path) == 0 {
+		return nodes
+	}
+
+	fullKey := prefix + path[0]
+	for i := range nodes {
+		if nodes[i].key == path[0] {
+			nodes[i].children = insertNode(nodes[i].children, path[1:], lines, values, fullKey+".")
+			return nodes
+		}
+	}
+
+	n := node{key: path[0], line: lines[fullKey], value: values[fullKey]}
+	n.children = insertNode(nil, path[1:], lines, values, fullKey+".")
+	return append(nodes, n)
+}
+
+// linesTOML is a helper function used by nodesTOML to find the line each key of the TOML
+// content is defined on. The keys of the returned map are dotted paths.
+func linesTOML(data []byte) map[string]int {
+	result := map[string]int{}
+	var table string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			end := strings.Index(text, "]")
+			if end < 0 {
+				continue
+			}
+			table = joinTOMLKey(strings.Trim(text[:end], "["))
+			if _, ok := result[table]; !ok {
+				result[table] = line
+			}
+			continue
+		}
+
+		if equal := strings.Index(text, "="); equal > 0 {
+			key := joinTOMLKey(text[:equal])
+			if table != "" {
+				key = table + "." + key
+			}
+			if _, ok := result[key]; !ok {
+				result[key] = line
+			}
+		}
+	}
+
+	return result
+}
+
+// joinTOMLKey is a helper function used by linesTOML to turn a possibly quoted and
+// dotted TOML key into a dotted path.
+func joinTOMLKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// nodesENV is a helper function used by checkStrict to list the keys of the ENV content.
+func nodesENV(data []byte) ([]node, error) {
+	dataEnv, err := godotenv.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	lines := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "export ")
+		if end := strings.IndexAny(text, "=:"); end > 0 {
+			key := strings.TrimSpace(text[:end])
+			if _, ok := lines[key]; !ok {
+				lines[key] = line
+			}
+		}
+	}
+
+	result := make([]node, 0, len(dataEnv))
+	for key := range dataEnv {
+		result = append(result, node{key: key, line: lines[key], value: dataEnv[key]})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].line != result[j].line {
+			return result[i].line < result[j].line
+		}
+		return result[i].key < result[j].key
+	})
+
+	return result, nil
+}
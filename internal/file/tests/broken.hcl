@@ -0,0 +1 @@
+not hcl
\ No newline at end of file
@@ -0,0 +1,5 @@
+field = "hclFieldValue"
+
+nested {
+  field = "hclNestedFieldValue"
+}
@@ -0,0 +1,6 @@
+field = "hclFieldValue"
+
+nested {
+  field = "hclNestedFieldValue"
+  fild  = "hclTypo"
+}
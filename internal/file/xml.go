@@ -0,0 +1,187 @@
+package file
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+// parseXML is a helper function used by Read to parse the XML content of the file.
+// It takes an io.Reader and a pointer to a struct where each field represents a
+// configuration option. The root element holds the configuration, and both the child
+// elements and the attributes of an element map to the fields of the nested struct.
+// The values are converted the same way as for .env files. The function returns an
+// error if the parsing process fails.
+func parseXML(r io.Reader, structPtr any, opts options.Options) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	entries, err := entriesXML(data)
+	if err != nil {
+		return err
+	}
+
+	return writeEntries(entries, structPtr, "xml", opts)
+}
+
+// xmlElement is a helper type used by entriesXML to keep track of an open element.
+type xmlElement struct {
+	key      string       // The dotted path of the element relative to the root element.
+	line     int          // The line of the element, starting at 1.
+	text     bytes.Buffer // The text content of the element.
+	children bool         // Whether the element has child elements.
+}
+
+// entriesXML is a helper function that reads the keys of the XML content. The keys of
+// the child elements and the attributes are prefixed with the path of their parent
+// element, leaving out the root element, and the value of an element is its trimmed text.
+// Elements with child elements have no value of their own.
+func entriesXML(data []byte) ([]entry, error) {
+	var result []entry
+	var stack []*xmlElement
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			line := lineAt(data, start)
+			tag := data[start:decoder.InputOffset()]
+
+			var key string
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = true
+				key = joinEntryKey(parent.key, t.Name.Local)
+			}
+
+			lines := attributeLines(tag)
+			for i, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+
+				attrLine := line
+				if i < len(lines) {
+					attrLine += lines[i]
+				}
+				result = append(result, entry{
+					key:   joinEntryKey(key, attr.Name.Local),
+					value: attr.Value,
+					line:  attrLine,
+				})
+			}
+
+			stack = append(stack, &xmlElement{key: key, line: line})
+
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+
+		case xml.EndElement:
+			element := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if element.key != "" && !element.children {
+				result = append(result, entry{
+					key:   element.key,
+					value: strings.TrimSpace(element.text.String()),
+					line:  element.line,
+				})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// attributeLines is a helper function used by entriesXML that scans the start tag, which
+// may span several lines, and returns the lines of its attributes relative to the first
+// line of the tag, in the order of the attributes.
+func attributeLines(tag []byte) []int {
+	var result []int
+
+	line := 0
+	i := bytes.IndexAny(tag, " \t\r\n")
+	for i >= 0 && i < len(tag) {
+		switch c := tag[i]; c {
+		case '\n':
+			line++
+			i++
+		case ' ', '\t', '\r', '=', '/', '>':
+			i++
+		case '"', '\'':
+			end := bytes.IndexByte(tag[i+1:], c)
+			if end < 0 {
+				return result
+			}
+			line += bytes.Count(tag[i+1:i+1+end], []byte("\n"))
+			i += end + 2
+		default:
+			result = append(result, line)
+			for i < len(tag) && strings.IndexByte(" \t\r\n=/>", tag[i]) < 0 {
+				i++
+			}
+		}
+	}
+
+	return result
+}
+
+// joinEntryKey is a helper function used by entriesXML that joins the path of an element
+// with the name of its child element or attribute.
+func joinEntryKey(parentKey, key string) string {
+	if parentKey == "" {
+		return key
+	}
+	return parentKey + "." + key
+}
+
+// includesXML is a helper function used by Read to list the files included by the XML
+// content. Since "$include" is not a valid element name, the files are listed in the
+// include processing instructions, separated by commas: <?include db.xml, cache.yaml?>.
+func includesXML(data []byte) ([]string, error) {
+	var result []string
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if inst, ok := token.(xml.ProcInst); ok && inst.Target == "include" {
+			for _, include := range strings.Split(string(inst.Inst), ",") {
+				if include = strings.TrimSpace(include); include != "" {
+					result = append(result, include)
+				}
+			}
+		}
+	}
+}
+
+// nodesXML is a helper function used by checkStrict to list the keys of the XML content.
+func nodesXML(data []byte) ([]node, error) {
+	entries, err := entriesXML(data)
+	if err != nil {
+		return nil, err
+	}
+	return nodesEntries(entries), nil
+}
@@ -0,0 +1,277 @@
+package flag
+
+import (
+	"bytes"
+	"fmt"
+	rf "reflect"
+	"regexp"
+	"strings"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/schema"
+)
+
+// The values of the path tag of a field.
+const (
+	pathFile = "file" // The value of the flag is the path of a file.
+	pathDir  = "dir"  // The value of the flag is the path of a directory.
+)
+
+// notIdentifier matches the characters of the program name that cannot be part of a shell
+// function name.
+var notIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// completionFlag describes a flag of the structure the way the shells complete it.
+type completionFlag struct {
+	long        string   // The name of the long flag without the dashes, e.g. http-port.
+	short       string   // The shorthand of the flag without the dash, e.g. p.
+	description string   // The description tag of the field.
+	values      []string // The values of the flag, from the oneof rule or true and false for bools.
+	path        string   // Whether the value is the path of a file or a directory, empty otherwise.
+}
+
+// Completion is a function that writes the completion script of the program for the shell,
+// which is bash, zsh or fish. The script completes the flags Read registers for the fields
+// of the structure, including the flag that selects the profile, and their values: the
+// values of the oneof rule of the validate tag, true and false for booleans, and the paths
+// of files or directories for the fields with the path tag set to file or dir. It returns
+// ErrUnsupportedShell for other shells and ErrInvalidPathTag for other values of the tag.
+func Completion(structPtr any, shell, program string, opts ...options.Option) ([]byte, error) {
+	if err := reflect.Validation(structPtr); err != nil {
+		return nil, fmt.Errorf("failed to validate in struct: %w", err)
+	}
+
+	o := options.New(opts...)
+	flags, err := completionFlags(rf.TypeOf(structPtr).Elem(), reflect.FieldKeys(structPtr, o.Naming), "")
+	if err != nil {
+		return nil, err
+	}
+
+	if o.ProfileFlag != "" && !hasFlag(flags, o.ProfileFlag) {
+		flags = append(flags, completionFlag{long: o.ProfileFlag, description: "The configuration profile"})
+	}
+
+	switch strings.ToLower(shell) {
+	case "bash":
+		return completionBash(flags, program), nil
+	case "zsh":
+		return completionZsh(flags, program), nil
+	case "fish":
+		return completionFish(flags, program), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedShell, shell)
+	}
+}
+
+// completionFlags is a recursive function used by Completion that describes the flags of
+// the fields in the order Read registers them, skipping the names already taken.
+func completionFlags(typeOf rf.Type, fieldKeys map[string]reflect.Keys, prefix string) ([]completionFlag, error) {
+	var result []completionFlag
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		fieldName := prefix + field.Name
+
+		if field.Type.Kind() == rf.Struct && !reflect.IsText(field.Type) {
+			nested, err := completionFlags(field.Type, fieldKeys, fieldName+".")
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range nested {
+				if !hasFlag(result, f.long) {
+					result = append(result, f)
+				}
+			}
+			continue
+		}
+
+		f := completionFlag{
+			long:        fieldKeys[fieldName].Flag,
+			short:       field.Tag.Get("s-flag"),
+			description: field.Tag.Get("description"),
+		}
+		if f.long == "" && f.short == "" || hasFlag(result, f.long) {
+			continue
+		}
+
+		rules, err := schema.ParseRules(field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rules of %s: %w", fieldName, err)
+		}
+		f.values = rules.OneOf
+		if field.Type.Kind() == rf.Bool && len(f.values) == 0 {
+			f.values = []string{"true", "false"}
+		}
+
+		switch f.path = field.Tag.Get("path"); f.path {
+		case "", pathFile, pathDir:
+		default:
+			return nil, fmt.Errorf("%w: %s: %q, expected %s or %s", ErrInvalidPathTag, fieldName, f.path, pathFile, pathDir)
+		}
+
+		result = append(result, f)
+	}
+
+	return result, nil
+}
+
+// hasFlag is a helper function that reports whether the long flag is one of the flags.
+func hasFlag(flags []completionFlag, long string) bool {
+	if long == "" {
+		return false
+	}
+
+	for _, f := range flags {
+		if f.long == long {
+			return true
+		}
+	}
+	return false
+}
+
+// completionBash is a helper function used by Completion to write the script for bash. The
+// values are completed after the flag, e.g. --mode d, and after the equal sign, e.g.
+// --mode=d, which bash splits into separate words.
+func completionBash(flags []completionFlag, program string) []byte {
+	function := "_" + notIdentifier.ReplaceAllString(program, "_") + "_completion"
+
+	var names []string
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# bash completion for %s\n\n", program)
+	fmt.Fprintf(&buf, "%s() {\n", function)
+	buf.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	buf.WriteString("    if [[ \"$prev\" == \"=\" ]]; then\n")
+	buf.WriteString("        prev=\"${COMP_WORDS[COMP_CWORD-2]}\"\n")
+	buf.WriteString("    elif [[ \"$cur\" == \"=\" ]]; then\n")
+	buf.WriteString("        prev=\"${COMP_WORDS[COMP_CWORD-1]}\" cur=\"\"\n")
+	buf.WriteString("    fi\n\n")
+	buf.WriteString("    case \"$prev\" in\n")
+	for _, f := range flags {
+		patterns := make([]string, 0, 2)
+		if f.long != "" {
+			patterns = append(patterns, "--"+f.long)
+			names = append(names, "--"+f.long)
+		}
+		if f.short != "" {
+			patterns = append(patterns, "-"+f.short)
+			names = append(names, "-"+f.short)
+		}
+
+		fmt.Fprintf(&buf, "        %s)\n", strings.Join(patterns, "|"))
+		switch {
+		case len(f.values) > 0:
+			fmt.Fprintf(&buf, "            COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", quoteShell(strings.Join(f.values, " ")))
+		case f.path == pathFile:
+			buf.WriteString("            COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+		case f.path == pathDir:
+			buf.WriteString("            COMPREPLY=($(compgen -d -- \"$cur\"))\n")
+		default:
+			buf.WriteString("            COMPREPLY=()\n")
+		}
+		buf.WriteString("            return\n")
+		buf.WriteString("            ;;\n")
+	}
+	buf.WriteString("    esac\n\n")
+	fmt.Fprintf(&buf, "    COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", quoteShell(strings.Join(names, " ")))
+	buf.WriteString("}\n\n")
+	fmt.Fprintf(&buf, "complete -o default -F %s %s\n", function, quoteShell(program))
+
+	return buf.Bytes()
+}
+
+// completionZsh is a helper function used by Completion to write the script for zsh, which
+// can be placed on the fpath as _program or sourced.
+func completionZsh(flags []completionFlag, program string) []byte {
+	function := "_" + notIdentifier.ReplaceAllString(program, "_")
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "#compdef %s\n\n", program)
+	fmt.Fprintf(&buf, "%s() {\n", function)
+	buf.WriteString("    _arguments -s")
+	for _, f := range flags {
+		action := ":value: "
+		switch {
+		case len(f.values) > 0:
+			values := make([]string, len(f.values))
+			for i, value := range f.values {
+				values[i] = strings.ReplaceAll(escapeZsh(value), " ", `\ `)
+			}
+			action = ":value:(" + strings.Join(values, " ") + ")"
+		case f.path == pathFile:
+			action = ":file:_files"
+		case f.path == pathDir:
+			action = ":directory:_files -/"
+		}
+		if f.description != "" {
+			action = "[" + escapeZsh(f.description) + "]" + action
+		}
+
+		var spec string
+		switch {
+		case f.long != "" && f.short != "":
+			spec = fmt.Sprintf("'(-%[1]s --%[2]s)'{-%[1]s,--%[2]s=}%[3]s", f.short, f.long, quoteShell(action))
+		case f.long != "":
+			spec = quoteShell("--" + f.long + "=" + action)
+		default:
+			spec = quoteShell("-" + f.short + action)
+		}
+		fmt.Fprintf(&buf, " \\\n        %s", spec)
+	}
+	buf.WriteString("\n}\n\n")
+	fmt.Fprintf(&buf, "if [[ \"$funcstack[1]\" == %q ]]; then\n", function)
+	fmt.Fprintf(&buf, "    %s \"$@\"\n", function)
+	buf.WriteString("else\n")
+	fmt.Fprintf(&buf, "    compdef %s %s\n", function, quoteShell(program))
+	buf.WriteString("fi\n")
+
+	return buf.Bytes()
+}
+
+// completionFish is a helper function used by Completion to write the script for fish.
+func completionFish(flags []completionFlag, program string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# fish completion for %s\n\n", program)
+	for _, f := range flags {
+		fmt.Fprintf(&buf, "complete -c %s", quoteFish(program))
+		if f.long != "" {
+			fmt.Fprintf(&buf, " -l %s", f.long)
+		}
+		if f.short != "" {
+			fmt.Fprintf(&buf, " -s %s", f.short)
+		}
+		if f.description != "" {
+			fmt.Fprintf(&buf, " -d %s", quoteFish(f.description))
+		}
+
+		switch {
+		case len(f.values) > 0:
+			fmt.Fprintf(&buf, " -x -a %s", quoteFish(strings.Join(f.values, " ")))
+		case f.path == pathFile:
+			buf.WriteString(" -r -F")
+		case f.path == pathDir:
+			buf.WriteString(" -x -a '(__fish_complete_directories)'")
+		default:
+			buf.WriteString(" -x")
+		}
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes()
+}
+
+// quoteShell is a helper function that quotes the text for bash and zsh.
+func quoteShell(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
+
+// quoteFish is a helper function that quotes the text for fish, where only the backslashes
+// and the single quotes are escaped within single quotes.
+func quoteFish(text string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(text) + "'"
+}
+
+// escapeZsh is a helper function used by completionZsh that escapes the characters the
+// specs of _arguments give a meaning to.
+func escapeZsh(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`, "(", `\(`, ")", `\)`).Replace(text)
+}
@@ -0,0 +1,145 @@
+package flag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+func Test_Completion(t *testing.T) {
+	type InStructHTTP struct {
+		Port int `flag:"http-port" validate:"required,gte=1" description:"HTTP port"`
+	}
+	type InStruct struct {
+		Mode   string `flag:"mode" s-flag:"m" validate:"oneof=dev prod" description:"Mode: dev or prod"`
+		Config string `flag:"config" path:"file" description:"Config file"`
+		Data   string `s-flag:"d" path:"dir"`
+		Debug  bool   `flag:"debug"`
+		Skip   string
+		HTTP   InStructHTTP
+	}
+	type InStructInvalidPath struct {
+		Config string `flag:"config" path:"yes"`
+	}
+	withProfile := func(o *options.Options) { o.ProfileFlag = "profile" }
+
+	tableTests := []struct {
+		name      string
+		structPtr any
+		shell     string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "Bash",
+			structPtr: &InStruct{},
+			shell:     "bash",
+			want: `# bash completion for my-app
+
+_my_app_completion() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    if [[ "$prev" == "=" ]]; then
+        prev="${COMP_WORDS[COMP_CWORD-2]}"
+    elif [[ "$cur" == "=" ]]; then
+        prev="${COMP_WORDS[COMP_CWORD-1]}" cur=""
+    fi
+
+    case "$prev" in
+        --mode|-m)
+            COMPREPLY=($(compgen -W 'dev prod' -- "$cur"))
+            return
+            ;;
+        --config)
+            COMPREPLY=($(compgen -f -- "$cur"))
+            return
+            ;;
+        -d)
+            COMPREPLY=($(compgen -d -- "$cur"))
+            return
+            ;;
+        --debug)
+            COMPREPLY=($(compgen -W 'true false' -- "$cur"))
+            return
+            ;;
+        --http-port)
+            COMPREPLY=()
+            return
+            ;;
+        --profile)
+            COMPREPLY=()
+            return
+            ;;
+    esac
+
+    COMPREPLY=($(compgen -W '--mode -m --config -d --debug --http-port --profile' -- "$cur"))
+}
+
+complete -o default -F _my_app_completion 'my-app'
+`,
+		},
+		{
+			name:      "Zsh",
+			structPtr: &InStruct{},
+			shell:     "zsh",
+			want: `#compdef my-app
+
+_my_app() {
+    _arguments -s \
+        '(-m --mode)'{-m,--mode=}'[Mode\: dev or prod]:value:(dev prod)' \
+        '--config=[Config file]:file:_files' \
+        '-d:directory:_files -/' \
+        '--debug=:value:(true false)' \
+        '--http-port=[HTTP port]:value: ' \
+        '--profile=[The configuration profile]:value: '
+}
+
+if [[ "$funcstack[1]" == "_my_app" ]]; then
+    _my_app "$@"
+else
+    compdef _my_app 'my-app'
+fi
+`,
+		},
+		{
+			name:      "Fish",
+			structPtr: &InStruct{},
+			shell:     "fish",
+			want: `# fish completion for my-app
+
+complete -c 'my-app' -l mode -s m -d 'Mode: dev or prod' -x -a 'dev prod'
+complete -c 'my-app' -l config -d 'Config file' -r -F
+complete -c 'my-app' -s d -x -a '(__fish_complete_directories)'
+complete -c 'my-app' -l debug -x -a 'true false'
+complete -c 'my-app' -l http-port -d 'HTTP port' -x
+complete -c 'my-app' -l profile -d 'The configuration profile' -x
+`,
+		},
+		{
+			name:      "Unsupported Shell",
+			structPtr: &InStruct{},
+			shell:     "powershell",
+			wantErr:   ErrUnsupportedShell,
+		},
+		{
+			name:      "Invalid Path Tag",
+			structPtr: &InStructInvalidPath{},
+			shell:     "bash",
+			wantErr:   ErrInvalidPathTag,
+		},
+	}
+
+	for _, tt := range tableTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Completion(tt.structPtr, tt.shell, "my-app", withProfile)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
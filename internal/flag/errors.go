@@ -0,0 +1,11 @@
+package flag
+
+import "fmt"
+
+var (
+	// ErrUnsupportedShell is returned when the completion script cannot be written for the shell
+	ErrUnsupportedShell = fmt.Errorf("unsupported shell")
+
+	// ErrInvalidPathTag is returned when the path tag of a field is neither file nor dir
+	ErrInvalidPathTag = fmt.Errorf("invalid path tag")
+)
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/dsbasko/go-cfg/internal/dflt"
+	"github.com/dsbasko/go-cfg/internal/options"
 	"github.com/dsbasko/go-cfg/internal/reflect"
 )
 
@@ -16,12 +17,15 @@ var (
 	once    sync.Once
 	flagSet *pflag.FlagSet
 	dataPtr map[string]*string
+	flags   map[string]*pflag.Flag
 )
 
 // Read is a function that reads the input structure, validates it, reads the default values,
 // parses the flags from the command line arguments and writes the values to the input structure.
+// Fields without a flag tag use the flag name derived from their canonical key, if any.
+// The flag that selects the profile of the configuration files is accepted as well.
 // It returns an error if any of these operations fail.
-func Read(structPtr any) error {
+func Read(structPtr any, opts ...options.Option) error {
 	if err := reflect.Validation(structPtr); err != nil {
 		return fmt.Errorf("failed to validate in struct: %w", err)
 	}
@@ -32,13 +36,20 @@ func Read(structPtr any) error {
 
 	once.Do(func() {
 		dataPtr = make(map[string]*string)
+		flags = make(map[string]*pflag.Flag)
 		flagSet = pflag.NewFlagSet("cfg", pflag.ContinueOnError)
 	})
 
-	if err := parseFlags(structPtr, flagSet, ""); err != nil {
+	o := options.New(opts...)
+	fieldKeys := reflect.FieldKeys(structPtr, o.Naming)
+	if err := parseFlags(structPtr, flagSet, fieldKeys, ""); err != nil {
 		return fmt.Errorf("failed to parse flags: %w", err)
 	}
 
+	if o.ProfileFlag != "" && flagSet.Lookup(o.ProfileFlag) == nil {
+		flagSet.String(o.ProfileFlag, "", "The configuration profile")
+	}
+
 	if err := flagSet.Parse(os.Args[1:]); err != nil {
 		return fmt.Errorf("failed to parse flags: %w", err)
 	}
@@ -53,13 +64,36 @@ func Read(structPtr any) error {
 		return fmt.Errorf("failed to write to struct: %w", err)
 	}
 
+	if o.Track != nil {
+		track(fieldKeys, o)
+	}
+
 	return nil
 }
 
+// track is a helper function used by Read to report the fields set by the command-line
+// flags to the Track callback of the options. Empty flags are skipped, since they leave
+// the fields unchanged.
+func track(fieldKeys map[string]reflect.Keys, opts options.Options) {
+	for field := range fieldKeys {
+		f, ok := flags[field]
+		if !ok || !f.Changed || f.Value.String() == "" {
+			continue
+		}
+
+		location := "--" + f.Name
+		if f.Name == "" {
+			location = "-" + f.Shorthand
+		}
+		opts.Track(field, location, f.Value.String())
+	}
+}
+
 // parseFlags is a recursive function that parses the flags from the input structure and
 // the command line arguments. It adds the flags to the flagSet and the dataPtr map.
+// The names of the flags come from fieldKeys, keyed by the fully qualified field names.
 // It returns an error if the parsing fails.
-func parseFlags(structPtr any, flagSet *pflag.FlagSet, prefix string) error {
+func parseFlags(structPtr any, flagSet *pflag.FlagSet, fieldKeys map[string]reflect.Keys, prefix string) error {
 	valueOf := rf.ValueOf(structPtr)
 	if valueOf.Kind() == rf.Ptr {
 		valueOf = valueOf.Elem()
@@ -67,7 +101,8 @@ func parseFlags(structPtr any, flagSet *pflag.FlagSet, prefix string) error {
 
 	for i := 0; i < valueOf.NumField(); i++ {
 		field := valueOf.Type().Field(i)
-		flagFullName := field.Tag.Get("flag")
+		fieldName := fmt.Sprintf("%s%s", prefix, field.Name)
+		flagFullName := fieldKeys[fieldName].Flag
 		flagShortName := field.Tag.Get("s-flag")
 		flagUsage := field.Tag.Get("description")
 
@@ -75,17 +110,20 @@ func parseFlags(structPtr any, flagSet *pflag.FlagSet, prefix string) error {
 			if err := parseFlags(
 				valueOf.Field(i).Addr().Interface(),
 				flagSet,
+				fieldKeys,
 				fmt.Sprintf("%s%s.", prefix, field.Name),
 			); err != nil {
 				return fmt.Errorf("failed to parse flags: %w", err)
 			}
 		}
 
-		fieldName := fmt.Sprintf("%s%s", prefix, field.Name)
 		foundFlag := flagSet.Lookup(flagFullName)
 		if _, ok := dataPtr[fieldName]; !ok && foundFlag == nil && (flagFullName != "" || flagShortName != "") {
 			dataPtr[fieldName] = new(string)
 			flagSet.StringVarP(dataPtr[fieldName], flagFullName, flagShortName, "", flagUsage)
+			if flags[fieldName] = flagSet.Lookup(flagFullName); flags[fieldName] == nil {
+				flags[fieldName] = flagSet.ShorthandLookup(flagShortName)
+			}
 		}
 	}
 
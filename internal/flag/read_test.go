@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dsbasko/go-cfg/internal/options"
 	"github.com/dsbasko/go-cfg/internal/reflect"
 )
 
@@ -121,3 +122,81 @@ func Test_Read(t *testing.T) {
 		})
 	}
 }
+
+func Test_Read_Canonical(t *testing.T) {
+	type InStructNested struct {
+		Field string `cfg:"field"`
+	}
+	type InStruct struct {
+		CanonicalField  string `cfg:"canonical-field"`
+		CanonicalNested InStructNested
+	}
+	osArgs := os.Args
+	defer func() { os.Args = osArgs }()
+
+	os.Args = append( //nolint:gocritic
+		osArgs,
+		"--canonical-field=FIELD_VALUE",
+		"--canonicalnested-field=NESTED_VALUE",
+	)
+
+	structPtr := &InStruct{}
+	err := Read(structPtr)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &InStruct{
+		CanonicalField: "FIELD_VALUE",
+		CanonicalNested: InStructNested{
+			Field: "NESTED_VALUE",
+		},
+	}, structPtr)
+}
+
+func Test_Read_ProfileFlag(t *testing.T) {
+	type InStruct struct {
+		ProfileField string `flag:"profile-field"`
+	}
+	osArgs := os.Args
+	defer func() { os.Args = osArgs }()
+
+	os.Args = append( //nolint:gocritic
+		osArgs,
+		"--profile-field=FIELD_VALUE",
+		"--app-profile=prod",
+	)
+
+	structPtr := &InStruct{}
+	err := Read(structPtr, func(o *options.Options) { o.ProfileFlag = "app-profile" })
+
+	assert.NoError(t, err)
+	assert.Equal(t, &InStruct{ProfileField: "FIELD_VALUE"}, structPtr)
+}
+
+func Test_Read_Track(t *testing.T) {
+	type InStruct struct {
+		Long  string `flag:"track-long"`
+		Short string `flag:"track-short" s-flag:"k"`
+		Unset string `flag:"track-unset"`
+	}
+	osArgs := os.Args
+	defer func() { os.Args = osArgs }()
+
+	os.Args = append( //nolint:gocritic
+		osArgs,
+		"--track-long=LONG_VALUE",
+		"-k", "SHORT_VALUE",
+	)
+
+	got := map[string][2]string{}
+	err := Read(&InStruct{}, func(o *options.Options) {
+		o.Track = func(field, location, value string) {
+			got[field] = [2]string{location, value}
+		}
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][2]string{
+		"Long":  {"--track-long", "LONG_VALUE"},
+		"Short": {"--track-short", "SHORT_VALUE"},
+	}, got)
+}
@@ -0,0 +1,57 @@
+package options
+
+import (
+	"os"
+	"time"
+)
+
+// Options represents the settings shared by the configuration readers.
+type Options struct {
+	Strict bool                     // Reject keys of configuration files that do not match any field.
+	Naming func(name string) string // Derive the canonical keys of the fields without a cfg tag.
+
+	LenientJSON    bool // Accept comments, trailing commas and unquoted keys in .json files.
+	ValidateSchema bool // Validate the values of configuration files against the schema of the struct.
+
+	Profile     string // The active profile, e.g. prod. Takes precedence over ProfileEnv and ProfileFlag.
+	ProfileEnv  string // The environment variable that selects the profile, e.g. APP_PROFILE.
+	ProfileFlag string // The command-line flag that selects the profile, e.g. profile.
+
+	Sources      []string          // The sources of the full load in the order they are read: file, env and flag.
+	PollInterval time.Duration     // How often the watched files are checked for changes. Negative disables the checks.
+	OnRead       func(path string) // Called with the path of every configuration file the file reader looks for.
+
+	// Called by the readers for every field a source sets, with the fully qualified name of the field,
+	// where the value comes from, e.g. config.yaml:12 or HTTP_PORT, and the value as the source has it.
+	Track func(field, location, value string)
+
+	ReloadSignals []os.Signal // The signals that make the watched configuration reload.
+	Logger        Logger      // Logs the results of the reloads. Nothing is logged if nil.
+	RejectFrozen  bool        // Fail the reloads that change the fields tagged with reload:"false".
+
+	EncryptionKey     string // The base64-encoded key of the encrypted values. Takes precedence over the others.
+	EncryptionKeyFile string // The file that holds the base64-encoded key of the encrypted values.
+	EncryptionKeyEnv  string // The environment variable that holds the key, GOCFG_ENCRYPTION_KEY by default.
+
+	RevealSecrets bool // Write the actual values of the secrets instead of redacting them when marshaling.
+}
+
+// Logger is the interface of the loggers the results of the reloads are written to,
+// which *log.Logger implements.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// Option is a function that changes the settings of the configuration readers.
+type Option func(*Options)
+
+// New returns the settings with all the provided options applied.
+func New(opts ...Option) Options {
+	var result Options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&result)
+		}
+	}
+	return result
+}
@@ -0,0 +1,235 @@
+package reflect
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CanonicalTag is the name of the struct tag that holds the canonical key of a field.
+// The names of the field in the environment variables, the command-line flags and the
+// configuration files are derived from the canonical key, unless the field has a tag
+// for the source itself.
+const CanonicalTag = "cfg"
+
+// Keys represents the names a field is known by in the configuration sources.
+type Keys struct {
+	Canonical string // The canonical key, e.g. http.read_timeout. Empty if the field has none.
+	Env       string // The name of the environment variable, e.g. HTTP_READ_TIMEOUT.
+	Flag      string // The name of the command-line flag, e.g. http-read-timeout.
+}
+
+// FieldKeys returns the keys of the fields of the struct pointed to by structPtr. The
+// keys of the map are the fully qualified names of the fields, the same as in ParseTag.
+// The canonical key of a field is its cfg tag, or its name converted by the naming
+// function if the tag is missing and the function is not nil. Nested structs prefix the
+// keys of their fields with their own canonical key, or with their lowercase name if they
+// have none. The env and flag tags take precedence over the names derived from the
+// canonical key.
+func FieldKeys(structPtr any, naming func(string) string) map[string]Keys {
+	typeOf := reflect.TypeOf(structPtr)
+	if typeOf.Kind() == reflect.Ptr {
+		typeOf = typeOf.Elem()
+	}
+
+	result := map[string]Keys{}
+	fieldKeysRecursive(typeOf, naming, "", "", result)
+	return result
+}
+
+// fieldKeysRecursive is a helper function for FieldKeys. The prefix parameter is used to
+// build the fully qualified names of the fields, and the parentKey parameter holds the
+// canonical key of the struct the fields belong to.
+func fieldKeysRecursive(
+	typeOf reflect.Type,
+	naming func(string) string,
+	prefix, parentKey string,
+	result map[string]Keys,
+) {
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		segment := Segment(field, naming)
+
+		if field.Type.Kind() == reflect.Struct {
+			if segment == "" {
+				segment = strings.ToLower(field.Name)
+			}
+			fieldKeysRecursive(
+				field.Type,
+				naming,
+				fmt.Sprintf("%s%s.", prefix, field.Name),
+				joinKey(parentKey, segment),
+				result,
+			)
+			continue
+		}
+
+		var keys Keys
+		if segment != "" {
+			keys.Canonical = joinKey(parentKey, segment)
+			keys.Env = EnvName(keys.Canonical)
+			keys.Flag = FlagName(keys.Canonical)
+		}
+		if env := tagName(field, "env"); env != "" {
+			keys.Env = env
+		}
+		if flag := tagName(field, "flag"); flag != "" {
+			keys.Flag = flag
+		}
+
+		result[fmt.Sprintf("%s%s", prefix, field.Name)] = keys
+	}
+}
+
+// IsText reports whether the type is a single value encoded as text, e.g. time.Time,
+// rather than a nested struct, which is the case if the type or a pointer to it
+// implements encoding.TextMarshaler or encoding.TextUnmarshaler.
+func IsText(typeOf reflect.Type) bool {
+	textMarshaler := reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshaler := reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	pointer := reflect.PointerTo(typeOf)
+	return typeOf.Implements(textMarshaler) || pointer.Implements(textMarshaler) || pointer.Implements(textUnmarshaler)
+}
+
+// Segment returns the canonical key of the field relative to the struct it belongs to.
+// It is the value of the cfg tag, or the name of the field converted by the naming
+// function if the tag is missing. It returns an empty string if neither is available.
+func Segment(field reflect.StructField, naming func(string) string) string {
+	if segment := tagName(field, CanonicalTag); segment != "" {
+		return segment
+	}
+
+	if naming != nil && field.PkgPath == "" {
+		return naming(field.Name)
+	}
+
+	return ""
+}
+
+// FileKey returns the path of keys that holds the value of the field in a configuration
+// file whose format maps keys to fields with the given tag, e.g. json or yaml. The tag of
+// the format takes precedence, then the canonical key split on dots, and finally the
+// name of the field the way the decoder of the format expects it.
+func FileKey(field reflect.StructField, tag string, naming func(string) string) []string {
+	if name := tagName(field, tag); name != "" {
+		return []string{name}
+	}
+
+	if segment := Segment(field, naming); segment != "" {
+		return strings.Split(segment, ".")
+	}
+
+	return []string{DecoderKey(field, tag)}
+}
+
+// FileKeys returns the dotted paths of keys that hold the values of the fields of the
+// struct pointed to by structPtr in a configuration file whose format maps keys to fields
+// with the given tag. The keys of the map are the fully qualified names of the fields,
+// the same as in ParseTag, and the paths are built with FileKey for every nested struct.
+func FileKeys(structPtr any, tag string, naming func(string) string) map[string]string {
+	typeOf := reflect.TypeOf(structPtr)
+	if typeOf.Kind() == reflect.Ptr {
+		typeOf = typeOf.Elem()
+	}
+
+	result := map[string]string{}
+	fileKeysRecursive(typeOf, tag, naming, "", "", result)
+	return result
+}
+
+// fileKeysRecursive is a helper function for FileKeys. The prefix parameter is used to
+// build the fully qualified names of the fields, and the parentKey parameter holds the
+// path of keys of the struct the fields belong to.
+func fileKeysRecursive(
+	typeOf reflect.Type,
+	tag string,
+	naming func(string) string,
+	prefix, parentKey string,
+	result map[string]string,
+) {
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if field.Tag.Get(tag) == "-" {
+			continue
+		}
+
+		key := joinKey(parentKey, strings.Join(FileKey(field, tag, naming), "."))
+		if field.Type.Kind() == reflect.Struct {
+			fileKeysRecursive(field.Type, tag, naming, fmt.Sprintf("%s%s.", prefix, field.Name), key, result)
+			continue
+		}
+
+		result[fmt.Sprintf("%s%s", prefix, field.Name)] = key
+	}
+}
+
+// DecoderKey returns the key the decoder of the format with the given tag expects for
+// the field: the name in the tag, or the name of the field, which YAML lowercases.
+func DecoderKey(field reflect.StructField, tag string) string {
+	if name := tagName(field, tag); name != "" {
+		return name
+	}
+
+	if tag == "yaml" {
+		return strings.ToLower(field.Name)
+	}
+
+	return field.Name
+}
+
+// HasCanonicalKeys reports whether the keys of the struct type or its nested structs
+// are derived from canonical keys, which is the case if any field has a cfg tag or a
+// naming function is used.
+func HasCanonicalKeys(typeOf reflect.Type, naming func(string) string) bool {
+	if naming != nil {
+		return true
+	}
+
+	for typeOf.Kind() == reflect.Ptr {
+		typeOf = typeOf.Elem()
+	}
+
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if tagName(field, CanonicalTag) != "" {
+			return true
+		}
+		if field.Type.Kind() == reflect.Struct && HasCanonicalKeys(field.Type, nil) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnvName converts a canonical key into the name of an environment variable,
+// e.g. http.read_timeout into HTTP_READ_TIMEOUT.
+func EnvName(key string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// FlagName converts a canonical key into the name of a command-line flag,
+// e.g. http.read_timeout into http-read-timeout.
+func FlagName(key string) string {
+	return strings.ToLower(strings.NewReplacer(".", "-", "_", "-").Replace(key))
+}
+
+// joinKey is a helper function that joins the canonical key of a struct with the key
+// of one of its fields.
+func joinKey(parentKey, key string) string {
+	if parentKey == "" {
+		return key
+	}
+	return parentKey + "." + key
+}
+
+// tagName is a helper function that returns the name part of the tag, which is the
+// value up to the first comma. It returns an empty string for the "-" name.
+func tagName(field reflect.StructField, tag string) string {
+	name := strings.Split(field.Tag.Get(tag), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,115 @@
+package reflect
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FieldKeys(t *testing.T) {
+	type InStructFld struct {
+		FldInt    int    `cfg:"field_int"`
+		FldString string `cfg:"field_string" env:"STRING" flag:"string"`
+		FldBool   bool
+	}
+	type InStruct struct {
+		FldString string      `cfg:"nested.field_string"`
+		FldStruct InStructFld `cfg:"struct"`
+		FldPlain  InStructFld
+	}
+
+	tableTests := []struct {
+		name      string
+		structPtr any
+		naming    func(string) string
+		wantKeys  map[string]Keys
+	}{
+		{
+			name:      "Canonical Tags",
+			structPtr: &InStruct{},
+			naming:    nil,
+			wantKeys: map[string]Keys{
+				"FldString":           {Canonical: "nested.field_string", Env: "NESTED_FIELD_STRING", Flag: "nested-field-string"},
+				"FldStruct.FldInt":    {Canonical: "struct.field_int", Env: "STRUCT_FIELD_INT", Flag: "struct-field-int"},
+				"FldStruct.FldString": {Canonical: "struct.field_string", Env: "STRING", Flag: "string"},
+				"FldStruct.FldBool":   {},
+				"FldPlain.FldInt":     {Canonical: "fldplain.field_int", Env: "FLDPLAIN_FIELD_INT", Flag: "fldplain-field-int"},
+				"FldPlain.FldString":  {Canonical: "fldplain.field_string", Env: "STRING", Flag: "string"},
+				"FldPlain.FldBool":    {},
+			},
+		},
+		{
+			name:      "Naming",
+			structPtr: &InStruct{},
+			naming:    SnakeCase,
+			wantKeys: map[string]Keys{
+				"FldString":           {Canonical: "nested.field_string", Env: "NESTED_FIELD_STRING", Flag: "nested-field-string"},
+				"FldStruct.FldInt":    {Canonical: "struct.field_int", Env: "STRUCT_FIELD_INT", Flag: "struct-field-int"},
+				"FldStruct.FldString": {Canonical: "struct.field_string", Env: "STRING", Flag: "string"},
+				"FldStruct.FldBool":   {Canonical: "struct.fld_bool", Env: "STRUCT_FLD_BOOL", Flag: "struct-fld-bool"},
+				"FldPlain.FldInt":     {Canonical: "fld_plain.field_int", Env: "FLD_PLAIN_FIELD_INT", Flag: "fld-plain-field-int"},
+				"FldPlain.FldString":  {Canonical: "fld_plain.field_string", Env: "STRING", Flag: "string"},
+				"FldPlain.FldBool":    {Canonical: "fld_plain.fld_bool", Env: "FLD_PLAIN_FLD_BOOL", Flag: "fld-plain-fld-bool"},
+			},
+		},
+	}
+
+	for _, tt := range tableTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantKeys, FieldKeys(tt.structPtr, tt.naming))
+		})
+	}
+}
+
+type textStruct struct {
+	value string
+}
+
+func (s *textStruct) UnmarshalText(text []byte) error {
+	s.value = string(text)
+	return nil
+}
+
+func Test_IsText(t *testing.T) {
+	type InStruct struct {
+		FldString string
+	}
+
+	tableTests := []struct {
+		name   string
+		typeOf reflect.Type
+		want   bool
+	}{
+		{name: "Marshaler", typeOf: reflect.TypeOf(time.Time{}), want: true},
+		{name: "Slice Marshaler", typeOf: reflect.TypeOf(net.IP{}), want: true},
+		{name: "Pointer Unmarshaler", typeOf: reflect.TypeOf(textStruct{}), want: true},
+		{name: "Struct", typeOf: reflect.TypeOf(InStruct{}), want: false},
+		{name: "String", typeOf: reflect.TypeOf(""), want: false},
+	}
+
+	for _, tt := range tableTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsText(tt.typeOf))
+		})
+	}
+}
+
+func Test_FileKey(t *testing.T) {
+	type InStruct struct {
+		FldTagged    string `cfg:"canonical" yaml:"tagged"`
+		FldCanonical string `cfg:"http.port"`
+		FldPlain     string
+	}
+	typeOf := reflect.TypeOf(InStruct{})
+
+	assert.Equal(t, []string{"tagged"}, FileKey(typeOf.Field(0), "yaml", nil))
+	assert.Equal(t, []string{"canonical"}, FileKey(typeOf.Field(0), "json", nil))
+	assert.Equal(t, []string{"http", "port"}, FileKey(typeOf.Field(1), "toml", nil))
+	assert.Equal(t, []string{"fldplain"}, FileKey(typeOf.Field(2), "yaml", nil))
+	assert.Equal(t, []string{"FldPlain"}, FileKey(typeOf.Field(2), "json", nil))
+	assert.Equal(t, []string{"fld-plain"}, FileKey(typeOf.Field(2), "json", KebabCase))
+	assert.True(t, HasCanonicalKeys(typeOf, nil))
+}
@@ -0,0 +1,71 @@
+package reflect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SnakeCase converts the name of a field to snake_case, e.g. ReadTimeout to read_timeout
+// and HTTPPort to http_port.
+func SnakeCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "_"))
+}
+
+// KebabCase converts the name of a field to kebab-case, e.g. ReadTimeout to read-timeout
+// and HTTPPort to http-port.
+func KebabCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+// CamelCase converts the name of a field to camelCase, e.g. ReadTimeout to readTimeout
+// and HTTPPort to httpPort.
+func CamelCase(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+			continue
+		}
+		words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords is a helper function that splits the name of a field into words. A word
+// starts at an upper case letter that follows a lower case letter or a digit, and at
+// the last upper case letter of an abbreviation followed by a lower case letter, so
+// HTTPReadTimeout is split into HTTP, Read and Timeout.
+func splitWords(name string) []string {
+	runes := []rune(name)
+
+	var result []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if runes[i] == '_' || runes[i] == '-' {
+			if start < i {
+				result = append(result, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+
+		previous := runes[i-1]
+		nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+			if start < i {
+				result = append(result, string(runes[start:i]))
+			}
+			start = i
+		}
+	}
+
+	if start < len(runes) {
+		result = append(result, string(runes[start:]))
+	}
+
+	return result
+}
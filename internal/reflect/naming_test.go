@@ -0,0 +1,61 @@
+package reflect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Naming(t *testing.T) {
+	tableTests := []struct {
+		name      string
+		fieldName string
+		wantSnake string
+		wantKebab string
+		wantCamel string
+	}{
+		{
+			name:      "Single Word",
+			fieldName: "Mode",
+			wantSnake: "mode",
+			wantKebab: "mode",
+			wantCamel: "mode",
+		},
+		{
+			name:      "Several Words",
+			fieldName: "ReadTimeout",
+			wantSnake: "read_timeout",
+			wantKebab: "read-timeout",
+			wantCamel: "readTimeout",
+		},
+		{
+			name:      "Abbreviation",
+			fieldName: "HTTPReadTimeout",
+			wantSnake: "http_read_timeout",
+			wantKebab: "http-read-timeout",
+			wantCamel: "httpReadTimeout",
+		},
+		{
+			name:      "Digits",
+			fieldName: "FldInt8",
+			wantSnake: "fld_int8",
+			wantKebab: "fld-int8",
+			wantCamel: "fldInt8",
+		},
+		{
+			name:      "Underscore",
+			fieldName: "Read_Timeout",
+			wantSnake: "read_timeout",
+			wantKebab: "read-timeout",
+			wantCamel: "readTimeout",
+		},
+	}
+
+	for _, tt := range tableTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantSnake, SnakeCase(tt.fieldName))
+			assert.Equal(t, tt.wantKebab, KebabCase(tt.fieldName))
+			assert.Equal(t, tt.wantCamel, CamelCase(tt.fieldName))
+		})
+	}
+}
@@ -11,7 +11,10 @@ import (
 // It uses reflection to iterate over the fields of the struct and calls the provided
 // function with the field name. The returned value from the function is then used to set
 // the value of the field in the struct. If the field is another struct, it recursively
-// calls itself to set the values of the nested struct's fields.
+// calls itself to set the values of the nested struct's fields. Empty values leave the
+// fields unchanged, so a source without a value for a field does not reset what an
+// earlier source set, e.g. a bool read from a file is kept when its environment variable
+// is not set.
 func WriteToStruct(structPtr any, fn func(fieldName string) string) error {
 	return writeToStructRecursive(structPtr, fn, "")
 }
@@ -110,8 +113,11 @@ func writeToStructRecursive(structPtr any, fn func(fieldName string) string, pre
 				valueOf.Field(i).SetFloat(valFloat)
 			}
 		case reflect.Bool:
-			valBool, _ := strconv.ParseBool(fn(fieldName))
-			valueOf.Field(i).SetBool(valBool)
+			val := fn(fieldName)
+			if val != "" {
+				valBool, _ := strconv.ParseBool(val)
+				valueOf.Field(i).SetBool(valBool)
+			}
 		}
 	}
 
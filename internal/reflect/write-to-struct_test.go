@@ -89,3 +89,44 @@ func Test_WriteToStruct(t *testing.T) {
 		})
 	}
 }
+
+func Test_WriteToStruct_Bool(t *testing.T) {
+	type InStruct struct {
+		FldBool bool
+	}
+
+	tableTests := []struct {
+		name       string
+		structPtr  *InStruct
+		value      string
+		wantStruct *InStruct
+	}{
+		{
+			name:       "Set",
+			structPtr:  &InStruct{},
+			value:      "true",
+			wantStruct: &InStruct{FldBool: true},
+		},
+		{
+			name:       "Unset",
+			structPtr:  &InStruct{FldBool: true},
+			value:      "false",
+			wantStruct: &InStruct{FldBool: false},
+		},
+		{
+			name:       "Empty Keeps Value",
+			structPtr:  &InStruct{FldBool: true},
+			value:      "",
+			wantStruct: &InStruct{FldBool: true},
+		},
+	}
+
+	for _, tt := range tableTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WriteToStruct(tt.structPtr, func(string) string { return tt.value })
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStruct, tt.structPtr)
+		})
+	}
+}
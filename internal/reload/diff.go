@@ -0,0 +1,106 @@
+package reload
+
+import (
+	"fmt"
+	rf "reflect"
+	"strings"
+	"sync"
+
+	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+// Change describes a field whose value differs between two configurations.
+type Change struct {
+	Path string // The fully qualified name of the field, e.g. HTTP.Port.
+	Old  any    // The value of the field in the previous configuration.
+	New  any    // The value of the field in the new configuration.
+}
+
+// Diff is a function that compares two pointers to structs of the same type and returns the
+// fields whose values differ, in the order of the fields. Nested structs are compared field
+// by field, other values as a whole, including the structs that are single values, e.g.
+// time.Time. Unexported fields are skipped. The values of the fields that hold secrets are
+// wrapped in secret.Secret, so printing the changes does not reveal them.
+func Diff(oldPtr, newPtr any) []Change {
+	var result []Change
+	secrets := secret.Fields(rf.TypeOf(oldPtr))
+	diffRecursive(rf.ValueOf(oldPtr).Elem(), rf.ValueOf(newPtr).Elem(), "", secrets, &result)
+	return result
+}
+
+// diffRecursive is a helper function for Diff. The prefix parameter is used to build the
+// fully qualified names of the fields.
+func diffRecursive(oldValue, newValue rf.Value, prefix string, secrets map[string]bool, result *[]Change) {
+	for i := 0; i < oldValue.NumField(); i++ {
+		field := oldValue.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		path := fmt.Sprintf("%s%s", prefix, field.Name)
+		if field.Type.Kind() == rf.Struct && !reflect.IsText(field.Type) {
+			diffRecursive(oldValue.Field(i), newValue.Field(i), path+".", secrets, result)
+			continue
+		}
+
+		oldField, newField := oldValue.Field(i).Interface(), newValue.Field(i).Interface()
+		if !rf.DeepEqual(oldField, newField) {
+			if secrets[path] {
+				oldField, newField = secret.Mask(oldField), secret.Mask(newField)
+			}
+			*result = append(*result, Change{Path: path, Old: oldField, New: newField})
+		}
+	}
+}
+
+// Under returns the changes of the fields in the subtree of the prefix, which is the fully
+// qualified name of a field or a nested struct, e.g. HTTP for HTTP.Port and HTTP.Host. The
+// empty prefix matches every change.
+func Under(changes []Change, prefix string) []Change {
+	if prefix == "" {
+		return changes
+	}
+
+	var result []Change
+	for _, change := range changes {
+		if change.Path == prefix || strings.HasPrefix(change.Path, prefix+".") {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+// Subscribers holds the callbacks subscribed to the changes of subtrees of the
+// configuration. The zero value is ready to use, and it is safe for concurrent use.
+type Subscribers struct {
+	mu   sync.Mutex
+	subs []subscription
+}
+
+// subscription is a callback subscribed to the changes of a subtree.
+type subscription struct {
+	prefix string
+	fn     func(Event)
+}
+
+// Add subscribes the callback to the changes of the subtree of the prefix, see Under.
+func (s *Subscribers) Add(prefix string, fn func(Event)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs = append(s.subs, subscription{prefix: prefix, fn: fn})
+}
+
+// Notify calls every callback whose subtree changed with the event, which only holds the
+// changes of the subtree. Failed reloads change nothing, so they are not passed on.
+func (s *Subscribers) Notify(event Event) {
+	s.mu.Lock()
+	subs := s.subs
+	s.mu.Unlock()
+
+	for _, sub := range subs {
+		if changes := Under(event.Changes, sub.prefix); len(changes) > 0 {
+			sub.fn(Event{Path: event.Path, Changes: changes})
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package reload
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+func Test_Diff(t *testing.T) {
+	type InStructNested struct {
+		Host string
+		Port int
+	}
+	type InStruct struct {
+		Mode     string
+		Tags     []string
+		HTTP     InStructNested
+		Password secret.Secret
+		Key      string `secret:"true"`
+		Started  time.Time
+		private  string
+	}
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		old  *InStruct
+		new  *InStruct
+		want []Change
+	}{
+		{
+			name: "Equal",
+			old:  &InStruct{Mode: "dev", Tags: []string{"a"}},
+			new:  &InStruct{Mode: "dev", Tags: []string{"a"}},
+			want: nil,
+		},
+		{
+			name: "Changed",
+			old:  &InStruct{Mode: "dev", Tags: []string{"a"}, HTTP: InStructNested{Host: "localhost", Port: 80}},
+			new:  &InStruct{Mode: "prod", Tags: []string{"a", "b"}, HTTP: InStructNested{Host: "localhost", Port: 8080}},
+			want: []Change{
+				{Path: "Mode", Old: "dev", New: "prod"},
+				{Path: "Tags", Old: []string{"a"}, New: []string{"a", "b"}},
+				{Path: "HTTP.Port", Old: 80, New: 8080},
+			},
+		},
+		{
+			name: "Secrets",
+			old:  &InStruct{Password: "hunter2", Key: "old"},
+			new:  &InStruct{Password: "swordfish", Key: "new"},
+			want: []Change{
+				{Path: "Password", Old: secret.Secret("hunter2"), New: secret.Secret("swordfish")},
+				{Path: "Key", Old: secret.Secret("old"), New: secret.Secret("new")},
+			},
+		},
+		{
+			name: "Text",
+			old:  &InStruct{Started: started},
+			new:  &InStruct{Started: started.Add(time.Hour)},
+			want: []Change{{Path: "Started", Old: started, New: started.Add(time.Hour)}},
+		},
+		{
+			name: "Unexported",
+			old:  &InStruct{private: "a"},
+			new:  &InStruct{private: "b"},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Diff(tt.old, tt.new))
+		})
+	}
+}
+
+func Test_Under(t *testing.T) {
+	changes := []Change{
+		{Path: "Mode"},
+		{Path: "HTTP.Port"},
+		{Path: "HTTP.TLS.Cert"},
+		{Path: "HTTPS.Port"},
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   []Change
+	}{
+		{name: "Empty", prefix: "", want: changes},
+		{name: "Subtree", prefix: "HTTP", want: []Change{{Path: "HTTP.Port"}, {Path: "HTTP.TLS.Cert"}}},
+		{name: "Field", prefix: "HTTP.Port", want: []Change{{Path: "HTTP.Port"}}},
+		{name: "None", prefix: "Log", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Under(changes, tt.prefix))
+		})
+	}
+}
+
+func Test_Subscribers(t *testing.T) {
+	var mu sync.Mutex
+	got := map[string][]Event{}
+	subscribe := func(s *Subscribers, prefix string) {
+		s.Add(prefix, func(event Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			got[prefix] = append(got[prefix], event)
+		})
+	}
+
+	var subscribers Subscribers
+	subscribe(&subscribers, "HTTP")
+	subscribe(&subscribers, "Log")
+	subscribe(&subscribers, "")
+
+	subscribers.Notify(Event{Path: "cfg.yaml", Changes: []Change{{Path: "Mode"}, {Path: "HTTP.Port", Old: 80, New: 8080}}})
+	subscribers.Notify(Event{Path: "cfg.yaml", Err: assert.AnError})
+
+	assert.Equal(t, map[string][]Event{
+		"HTTP": {{Path: "cfg.yaml", Changes: []Change{{Path: "HTTP.Port", Old: 80, New: 8080}}}},
+		"":     {{Path: "cfg.yaml", Changes: []Change{{Path: "Mode"}, {Path: "HTTP.Port", Old: 80, New: 8080}}}},
+	}, got)
+}
@@ -0,0 +1,79 @@
+package reload
+
+import (
+	"fmt"
+	rf "reflect"
+	"strings"
+
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// ErrRestartRequired is returned when a reload changes fields that cannot be reloaded
+var ErrRestartRequired = fmt.Errorf("restart required")
+
+// frozenTag is the struct tag that marks the fields that cannot be reloaded with the
+// reload:"false" value.
+const frozenTag = "reload"
+
+// frozenFields is a helper function that returns the fully qualified names of the fields
+// of the struct type that cannot be reloaded. The fields of a nested struct tagged with
+// reload:"false" cannot be reloaded either, while the structs that are single values, e.g.
+// time.Time, are fields themselves.
+func frozenFields(typeOf rf.Type) map[string]bool {
+	result := map[string]bool{}
+	frozenFieldsRecursive(typeOf, "", false, result)
+	return result
+}
+
+// frozenFieldsRecursive is a helper function for frozenFields. The frozen parameter
+// reports whether the struct itself cannot be reloaded.
+func frozenFieldsRecursive(typeOf rf.Type, prefix string, frozen bool, result map[string]bool) {
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		path := fmt.Sprintf("%s%s", prefix, field.Name)
+		fieldFrozen := frozen || field.Tag.Get(frozenTag) == "false"
+
+		if field.Type.Kind() == rf.Struct && !reflect.IsText(field.Type) {
+			frozenFieldsRecursive(field.Type, path+".", fieldFrozen, result)
+			continue
+		}
+
+		if fieldFrozen {
+			result[path] = true
+		}
+	}
+}
+
+// splitFrozen is a helper function that splits the changes into the ones of the fields
+// that can be reloaded and the ones of the frozen fields.
+func splitFrozen(changes []Change, frozen map[string]bool) (reloaded, restart []Change) {
+	for _, change := range changes {
+		if frozen[change.Path] {
+			restart = append(restart, change)
+		} else {
+			reloaded = append(reloaded, change)
+		}
+	}
+	return reloaded, restart
+}
+
+// restoreFrozen is a helper function that writes the old values of the changed frozen
+// fields back into the fresh structure.
+func restoreFrozen(freshPtr, currentPtr any, restart []Change) {
+	for _, change := range restart {
+		fresh, current := rf.ValueOf(freshPtr).Elem(), rf.ValueOf(currentPtr).Elem()
+		for _, name := range strings.Split(change.Path, ".") {
+			fresh, current = fresh.FieldByName(name), current.FieldByName(name)
+		}
+		fresh.Set(current)
+	}
+}
+
+// paths is a helper function that returns the fully qualified names of the changed fields.
+func paths(changes []Change) []string {
+	result := make([]string, len(changes))
+	for i, change := range changes {
+		result[i] = change.Path
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package reload
+
+import (
+	"context"
+	"os"
+	"path"
+	rf "reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+func Test_frozenFields(t *testing.T) {
+	type InStructNested struct {
+		DSN  string
+		Pool int `reload:"true"`
+	}
+	type InStruct struct {
+		Port    int `reload:"false"`
+		Level   string
+		DB      InStructNested `reload:"false"`
+		Metrics InStructNested
+		Started time.Time `reload:"false"`
+	}
+
+	assert.Equal(t, map[string]bool{
+		"Port":    true,
+		"Started": true,
+		"DB.DSN":  true,
+		"DB.Pool": true,
+	}, frozenFields(rf.TypeOf(InStruct{})))
+}
+
+type frozenStruct struct {
+	Port    int       `yaml:"port" reload:"false"`
+	Level   string    `yaml:"level"`
+	Started time.Time `yaml:"started" reload:"false"`
+}
+
+func Test_Watch_Frozen(t *testing.T) {
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	restart := []Change{
+		{Path: "Port", Old: 80, New: 8080},
+		{Path: "Started", Old: started, New: started.Add(time.Hour)},
+	}
+
+	tests := []struct {
+		name        string
+		reject      bool
+		wantErr     error
+		wantChanges []Change
+		wantRestart []Change
+		wantStruct  *frozenStruct
+	}{
+		{
+			name:        "Warning",
+			wantChanges: []Change{{Path: "Level", Old: "info", New: "debug"}},
+			wantRestart: restart,
+			wantStruct:  &frozenStruct{Port: 80, Level: "debug", Started: started},
+		},
+		{
+			name:        "Reject",
+			reject:      true,
+			wantErr:     ErrRestartRequired,
+			wantRestart: restart,
+			wantStruct:  &frozenStruct{Port: 80, Level: "info", Started: started},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := path.Join(t.TempDir(), "cfg.yaml")
+			assert.NoError(t, os.WriteFile(filePath, []byte("port: 80\nlevel: info\nstarted: 2024-01-02T03:04:05Z\n"), 0o600))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := make(chan Event, 1)
+			structPtr := &frozenStruct{}
+			err := Watch(ctx, filePath, structPtr, func(event Event) { events <- event }, func(o *options.Options) {
+				o.Sources = []string{SourceFile}
+				o.PollInterval = 10 * time.Millisecond
+				o.RejectFrozen = tt.reject
+			})
+			assert.NoError(t, err)
+
+			replaceFile(t, filePath, "port: 8080\nlevel: debug\nstarted: 2024-01-02T04:04:05Z\n")
+			select {
+			case event := <-events:
+				assert.ErrorIs(t, event.Err, tt.wantErr)
+				assert.Equal(t, tt.wantChanges, event.Changes)
+				assert.Equal(t, tt.wantRestart, event.Restart)
+			case <-time.After(5 * time.Second):
+				t.Fatal("no reload event")
+			}
+			assert.Equal(t, tt.wantStruct, structPtr)
+		})
+	}
+}
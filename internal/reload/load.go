@@ -0,0 +1,107 @@
+package reload
+
+import (
+	"fmt"
+	rf "reflect"
+
+	"github.com/dsbasko/go-cfg/internal/dflt"
+	"github.com/dsbasko/go-cfg/internal/env"
+	"github.com/dsbasko/go-cfg/internal/file"
+	"github.com/dsbasko/go-cfg/internal/flag"
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+// The sources of the full load.
+const (
+	SourceFile = "file"
+	SourceEnv  = "env"
+	SourceFlag = "flag"
+)
+
+// defaultSources is the order of the sources used when the options do not set one:
+// the configuration file, then the environment variables and finally the flags.
+var defaultSources = []string{SourceFile, SourceEnv, SourceFlag}
+
+// Load is a function that runs the full load pipeline into the provided cfg structure. The
+// default values are written first, then the sources are read in the order the options set,
+// by default the file at the path, the environment variables and the command-line flags.
+// Finally the structure is validated with its Validate method, if it has one. The function
+// returns the origins of the values of the fields, or an error if any of these steps fail.
+func Load(path string, structPtr any, opts ...options.Option) (Provenance, error) {
+	origins, err := load(path, structPtr, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return explain(rf.TypeOf(structPtr).Elem(), origins), nil
+}
+
+// load is a helper function for Load that returns the origins of the fields keyed by their
+// fully qualified names, which the watcher keeps between the reloads.
+func load(path string, structPtr any, opts ...options.Option) (map[string]Origin, error) {
+	if err := reflect.Validation(structPtr); err != nil {
+		return nil, fmt.Errorf("error validating struct: %w", err)
+	}
+
+	if err := dflt.Apply(structPtr); err != nil {
+		return nil, fmt.Errorf("error setting default values: %w", err)
+	}
+
+	// The other readers write the defaults once per process, which must happen before the
+	// sources are read, not in between.
+	if err := dflt.Read(structPtr); err != nil {
+		return nil, fmt.Errorf("error setting default values: %w", err)
+	}
+
+	origins := tracker{}
+	if err := origins.defaults(structPtr); err != nil {
+		return nil, fmt.Errorf("error setting default values: %w", err)
+	}
+
+	sources := options.New(opts...).Sources
+	if len(sources) == 0 {
+		sources = defaultSources
+	}
+
+	for _, source := range sources {
+		sourceOpts := append(opts[:len(opts):len(opts)], origins.option(source))
+
+		var err error
+		switch source {
+		case SourceFile:
+			err = file.Read(path, structPtr, sourceOpts...)
+		case SourceEnv:
+			err = env.Read(structPtr, sourceOpts...)
+		case SourceFlag:
+			err = flag.Read(structPtr, sourceOpts...)
+		default:
+			err = fmt.Errorf("unknown source %q", source)
+		}
+		if err != nil {
+			return nil, secret.RedactError(err, secret.Values(structPtr))
+		}
+	}
+
+	if err := validate(structPtr); err != nil {
+		return nil, secret.RedactError(err, secret.Values(structPtr))
+	}
+
+	return origins, nil
+}
+
+// validate is a helper function used by Load to call the Validate method of the
+// structure, if it has one.
+func validate(structPtr any) error {
+	validator, ok := structPtr.(interface{ Validate() error })
+	if !ok {
+		return nil
+	}
+
+	if err := validator.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return nil
+}
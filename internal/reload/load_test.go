@@ -0,0 +1,131 @@
+package reload
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+type loadStruct struct {
+	Field string `yaml:"field" env:"RELOAD_FIELD" flag:"reload-field" default:"defaultValue"`
+	Port  int    `yaml:"port" default:"80"`
+}
+
+func (s *loadStruct) Validate() error {
+	if s.Port < 0 {
+		return errors.New("port must not be negative")
+	}
+	return nil
+}
+
+type secretStruct struct {
+	Password secret.Secret `yaml:"password"`
+}
+
+func (s *secretStruct) Validate() error {
+	if len(s.Password) < 10 {
+		return fmt.Errorf("password %q is too short", s.Password.Reveal())
+	}
+	return nil
+}
+
+func Test_Load(t *testing.T) {
+	dir := t.TempDir()
+	filePath := path.Join(dir, "cfg.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte("field: fileValue\nport: 8080\n"), 0o600))
+	invalidPath := path.Join(dir, "invalid.yaml")
+	assert.NoError(t, os.WriteFile(invalidPath, []byte("port: -1\n"), 0o600))
+
+	tests := []struct {
+		name       string
+		path       string
+		sources    []string
+		env        string
+		args       []string
+		wantStruct *loadStruct
+		wantErr    string
+	}{
+		{
+			name:       "Defaults",
+			path:       path.Join(dir, "missing.yaml"),
+			sources:    []string{SourceEnv},
+			wantStruct: &loadStruct{Field: "defaultValue", Port: 80},
+		},
+		{
+			name:       "File",
+			path:       filePath,
+			sources:    []string{SourceFile},
+			env:        "envValue",
+			wantStruct: &loadStruct{Field: "fileValue", Port: 8080},
+		},
+		{
+			name:       "Env Over File",
+			path:       filePath,
+			sources:    []string{SourceFile, SourceEnv},
+			env:        "envValue",
+			wantStruct: &loadStruct{Field: "envValue", Port: 8080},
+		},
+		{
+			name:       "File Over Env",
+			path:       filePath,
+			sources:    []string{SourceEnv, SourceFile},
+			env:        "envValue",
+			wantStruct: &loadStruct{Field: "fileValue", Port: 8080},
+		},
+		{
+			name:       "Default Sources",
+			path:       filePath,
+			env:        "envValue",
+			args:       []string{"--reload-field=flagValue"},
+			wantStruct: &loadStruct{Field: "flagValue", Port: 8080},
+		},
+		{
+			name:    "Validate Error",
+			path:    invalidPath,
+			sources: []string{SourceFile},
+			wantErr: "invalid configuration: port must not be negative",
+		},
+		{
+			name:    "Unknown Source",
+			path:    filePath,
+			sources: []string{"etcd"},
+			wantErr: `unknown source "etcd"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("RELOAD_FIELD", tt.env)
+			}
+			args := os.Args
+			os.Args = append([]string{args[0]}, tt.args...)
+			defer func() { os.Args = args }()
+
+			structPtr := &loadStruct{}
+			_, err := Load(tt.path, structPtr, func(o *options.Options) { o.Sources = tt.sources })
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStruct, structPtr)
+		})
+	}
+}
+
+func Test_Load_SecretError(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "cfg.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte("password: hunter2\n"), 0o600))
+
+	_, err := Load(filePath, &secretStruct{}, func(o *options.Options) { o.Sources = []string{SourceFile} })
+	assert.EqualError(t, err, `invalid configuration: password "[REDACTED]" is too short`)
+}
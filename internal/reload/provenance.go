@@ -0,0 +1,161 @@
+package reload
+
+import (
+	"fmt"
+	"io"
+	rf "reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+// SourceDefault is the source of the values of the default tags.
+const SourceDefault = "default"
+
+// Origin describes where the value of a field comes from.
+type Origin struct {
+	Path     string // The fully qualified name of the field, e.g. HTTP.Port.
+	Source   string // The source that set the field: default, file, env or flag. Empty if none did.
+	Location string // Where the value is defined, e.g. config.yaml:12, HTTP_PORT or --http-port.
+	Value    string // The value as the source has it.
+}
+
+// Provenance lists the origins of the fields of a configuration in the order of the fields.
+type Provenance []Origin
+
+// Lookup returns the origin of the field with the fully qualified name, e.g. HTTP.Port.
+func (p Provenance) Lookup(path string) (Origin, bool) {
+	for _, origin := range p {
+		if origin.Path == path {
+			return origin, true
+		}
+	}
+	return Origin{}, false
+}
+
+// WriteTo writes the origins to the writer as a table with the columns of the field,
+// the source, the location and the value.
+func (p Provenance) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	table := tabwriter.NewWriter(counter, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(table, "FIELD\tSOURCE\tLOCATION\tVALUE")
+	for _, origin := range p {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n",
+			origin.Path, orDash(origin.Source), orDash(origin.Location), orDash(origin.Value))
+	}
+
+	err := table.Flush()
+	return counter.n, err
+}
+
+// String returns the origins as the table written by WriteTo.
+func (p Provenance) String() string {
+	var b strings.Builder
+	_, _ = p.WriteTo(&b)
+	return b.String()
+}
+
+// countingWriter is a helper type used by WriteTo to count the written bytes.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes the bytes to the underlying writer and counts them.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// orDash is a helper function that replaces an empty cell of the table with a dash.
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// explain is a helper function used by Load and Publish that lists the origins of the
+// fields of the struct type: which source set every field last, where the value is defined
+// and the value as the source has it. Every exported field is listed, in the order of the
+// fields, and the fields no source set have an empty source. The values of the fields that
+// hold secrets are redacted.
+func explain(typeOf rf.Type, origins map[string]Origin) Provenance {
+	secrets := secret.Fields(typeOf)
+	var result Provenance
+	for _, path := range fieldPaths(typeOf, "") {
+		origin, ok := origins[path]
+		if !ok {
+			origin = Origin{Path: path}
+		}
+		if secrets[path] && origin.Value != "" {
+			origin.Value = secret.Redacted
+		}
+		result = append(result, origin)
+	}
+	return result
+}
+
+// fieldPaths is a helper function used by explain that returns the fully qualified names of
+// the exported fields of the struct type in their order, the same way as Diff.
+func fieldPaths(typeOf rf.Type, prefix string) []string {
+	var result []string
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		path := prefix + field.Name
+		if field.Type.Kind() == rf.Struct && !reflect.IsText(field.Type) {
+			result = append(result, fieldPaths(field.Type, path+".")...)
+			continue
+		}
+		result = append(result, path)
+	}
+	return result
+}
+
+// tracker collects the origins of the fields while Load reads the sources.
+type tracker map[string]Origin
+
+// defaults is a helper method that records the fields with default values.
+func (t tracker) defaults(structPtr any) error {
+	parsedStruct, err := reflect.ParseTag(structPtr, "default")
+	if err != nil {
+		return fmt.Errorf("error parsing struct: %w", err)
+	}
+
+	for field, tag := range parsedStruct {
+		if tag.TagValue != "" {
+			t[field] = Origin{Path: field, Source: SourceDefault, Location: "default tag", Value: tag.TagValue}
+		}
+	}
+	return nil
+}
+
+// option is a helper method that returns the option that records the fields the source sets.
+func (t tracker) option(source string) options.Option {
+	return func(o *options.Options) {
+		o.Track = func(field, location, value string) {
+			t[field] = Origin{Path: field, Source: source, Location: location, Value: value}
+		}
+	}
+}
+
+// restoreOrigins is a helper function that writes the origins the changed frozen fields
+// have in the current configuration to the origins of the fresh one, see restoreFrozen.
+func restoreOrigins(fresh, current map[string]Origin, restart []Change) {
+	for _, change := range restart {
+		if origin, ok := current[change.Path]; ok {
+			fresh[change.Path] = origin
+		} else {
+			delete(fresh, change.Path)
+		}
+	}
+}
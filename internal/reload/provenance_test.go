@@ -0,0 +1,108 @@
+package reload
+
+import (
+	"context"
+	"os"
+	"path"
+	rf "reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+type provenanceStruct struct {
+	Host string `yaml:"host" default:"localhost"`
+	Port int    `yaml:"port" env:"PROVENANCE_PORT" default:"80"`
+	Log  struct {
+		Level string `yaml:"level"`
+	} `yaml:"log"`
+	Mode    string        `yaml:"mode" reload:"false"`
+	Token   secret.Secret `yaml:"token"`
+	Started time.Time     `yaml:"started"`
+}
+
+func Test_Explain(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "cfg.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte("port: 8080\nlog:\n  level: debug\ntoken: hunter2\nstarted: 2024-01-02T03:04:05Z\n"), 0o600))
+	t.Setenv("PROVENANCE_PORT", "9090")
+
+	provenance, err := Load(filePath, &provenanceStruct{}, func(o *options.Options) {
+		o.Sources = []string{SourceFile, SourceEnv}
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, Provenance{
+		{Path: "Host", Source: SourceDefault, Location: "default tag", Value: "localhost"},
+		{Path: "Port", Source: SourceEnv, Location: "PROVENANCE_PORT", Value: "9090"},
+		{Path: "Log.Level", Source: SourceFile, Location: filePath + ":3", Value: "debug"},
+		{Path: "Mode"},
+		{Path: "Token", Source: SourceFile, Location: filePath + ":4", Value: "[REDACTED]"},
+		{Path: "Started", Source: SourceFile, Location: filePath + ":5", Value: "2024-01-02T03:04:05Z"},
+	}, provenance)
+
+	origin, ok := provenance.Lookup("Log.Level")
+	assert.True(t, ok)
+	assert.Equal(t, SourceFile, origin.Source)
+
+	_, ok = provenance.Lookup("Missing")
+	assert.False(t, ok)
+}
+
+func Test_Provenance_String(t *testing.T) {
+	provenance := Provenance{
+		{Path: "Host", Source: SourceDefault, Location: "default tag", Value: "localhost"},
+		{Path: "Log.Level", Source: SourceFile, Location: "cfg.yaml:3", Value: "debug"},
+		{Path: "Mode"},
+	}
+
+	assert.Equal(t, ""+
+		"FIELD      SOURCE   LOCATION     VALUE\n"+
+		"Host       default  default tag  localhost\n"+
+		"Log.Level  file     cfg.yaml:3   debug\n"+
+		"Mode       -        -            -\n",
+		provenance.String())
+}
+
+func Test_explain_NotSet(t *testing.T) {
+	assert.Equal(t, Provenance{{Path: "Host"}, {Path: "Port"}, {Path: "Log.Level"}, {Path: "Mode"}, {Path: "Token"}, {Path: "Started"}},
+		explain(rf.TypeOf(provenanceStruct{}), nil))
+}
+
+func Test_Publish_Explain(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "cfg.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte("mode: dev\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	published := make(chan Provenance, 1)
+	err := Publish(ctx, filePath, rf.TypeOf(provenanceStruct{}), func(_ any, provenance Provenance) {
+		published <- provenance
+	}, nil, func(o *options.Options) {
+		o.Sources = []string{SourceFile}
+		o.PollInterval = 10 * time.Millisecond
+	})
+	assert.NoError(t, err)
+
+	origin, _ := (<-published).Lookup("Mode")
+	assert.Equal(t, filePath+":1", origin.Location)
+
+	replaceFile(t, filePath, "host: example.com\n\nmode: prod\n")
+	var provenance Provenance
+	select {
+	case provenance = <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no published configuration")
+	}
+
+	host, _ := provenance.Lookup("Host")
+	assert.Equal(t, Origin{Path: "Host", Source: SourceFile, Location: filePath + ":1", Value: "example.com"}, host)
+
+	// Mode cannot be reloaded, so it keeps the origin of the first load.
+	mode, _ := provenance.Lookup("Mode")
+	assert.Equal(t, Origin{Path: "Mode", Source: SourceFile, Location: filePath + ":1", Value: "dev"}, mode)
+}
@@ -0,0 +1,251 @@
+package reload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	rf "reflect"
+	"strings"
+	"time"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// defaultPollInterval is how often the configuration files are checked for changes when
+// the options do not set the interval.
+const defaultPollInterval = time.Second
+
+// Event describes the result of a reload of the configuration.
+type Event struct {
+	Path    string   // The path of the configuration file.
+	Err     error    // The error of the failed reload, in which case the configuration is left unchanged.
+	Changes []Change // The fields changed by the reload. Empty if the reload failed or changed nothing.
+	Restart []Change // The changes of the fields tagged with reload:"false", which keep their old values.
+}
+
+// fileState is the state of a configuration file used to detect its changes.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// watcher keeps the state of the configuration files between the checks.
+type watcher struct {
+	path     string
+	typeOf   rf.Type
+	publish  func(structPtr any, provenance Provenance)
+	onChange func(Event)
+	opts     []options.Option
+	interval time.Duration
+	signals  chan os.Signal
+	logger   options.Logger
+	files    map[string]fileState
+	current  any
+	origins  map[string]Origin
+	frozen   map[string]bool
+	reject   bool
+}
+
+// Watch is a function that loads the configuration into the provided cfg structure with
+// Load and reloads it every time one of the files it reads changes, including the included
+// files and the file of the profile. The files are polled until the context is done, and
+// the configuration is also reloaded on every signal the options list.
+//
+// Every reload runs the full load pipeline into a fresh structure, and only a structure
+// that loaded and validated successfully replaces the content of cfg, after which onChange
+// is called with the changed fields. If the reload fails, cfg is left unchanged and onChange
+// is called with the error. Fields tagged with reload:"false" keep the values of the first
+// load, and their changes are reported in the Restart field of the event, or fail the reload
+// with ErrRestartRequired if the options say so. The function returns an error if the initial load fails.
+func Watch(ctx context.Context, path string, structPtr any, onChange func(Event), opts ...options.Option) error {
+	if err := reflect.Validation(structPtr); err != nil {
+		return fmt.Errorf("error validating struct: %w", err)
+	}
+
+	publish := func(fresh any, _ Provenance) {
+		rf.ValueOf(structPtr).Elem().Set(rf.ValueOf(fresh).Elem())
+	}
+	return Publish(ctx, path, rf.TypeOf(structPtr).Elem(), publish, onChange, opts...)
+}
+
+// Publish is similar to Watch, but every configuration, including the initial one, is
+// loaded into a fresh structure of the struct type, which is passed to publish instead
+// of being copied, together with the origins of its fields. A published structure is never
+// changed afterwards, so it can be shared with the readers of the configuration.
+func Publish(
+	ctx context.Context,
+	path string,
+	typeOf rf.Type,
+	publish func(structPtr any, provenance Provenance),
+	onChange func(Event),
+	opts ...options.Option,
+) error {
+	if typeOf.Kind() != rf.Struct {
+		return fmt.Errorf("error validating struct: %w", reflect.ErrNotStruct)
+	}
+
+	o := options.New(opts...)
+	w := &watcher{
+		path:     path,
+		typeOf:   typeOf,
+		publish:  publish,
+		onChange: onChange,
+		opts:     opts,
+		interval: o.PollInterval,
+		logger:   o.Logger,
+		frozen:   frozenFields(typeOf),
+		reject:   o.RejectFrozen,
+	}
+	if w.interval == 0 {
+		w.interval = defaultPollInterval
+	}
+
+	fresh := rf.New(typeOf).Interface()
+	files, origins, err := w.load(fresh)
+	if err != nil {
+		return err
+	}
+	w.files = files
+	w.current, w.origins = fresh, origins
+	publish(fresh, explain(typeOf, origins))
+
+	// The signals are subscribed to before the function returns, so none sent afterwards is missed.
+	if len(o.ReloadSignals) > 0 {
+		w.signals = make(chan os.Signal, 1)
+		signal.Notify(w.signals, o.ReloadSignals...)
+	}
+
+	go w.run(ctx)
+	return nil
+}
+
+// run is a helper method that polls the files and waits for the signals until the
+// context is done. A negative interval disables the polling.
+func (w *watcher) run(ctx context.Context) {
+	var ticks <-chan time.Time
+	if w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	if w.signals != nil {
+		defer signal.Stop(w.signals)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticks:
+			if w.changed() {
+				w.reload("file changed")
+			}
+		case sig := <-w.signals:
+			w.reload(fmt.Sprintf("signal %s", sig))
+		}
+	}
+}
+
+// load is a helper method that runs Load into the structure and returns the states of the
+// files it read and the origins of the fields.
+func (w *watcher) load(structPtr any) (map[string]fileState, map[string]Origin, error) {
+	files := map[string]fileState{}
+	track := func(o *options.Options) {
+		o.OnRead = func(path string) {
+			files[path] = stat(path)
+		}
+	}
+
+	origins, err := load(w.path, structPtr, append(w.opts[:len(w.opts):len(w.opts)], track)...)
+	return files, origins, err
+}
+
+// reload is a helper method that loads the configuration into a fresh structure and, if
+// it succeeds, publishes it. The reason is what triggered the reload, used in the logs.
+func (w *watcher) reload(reason string) {
+	fresh := rf.New(w.typeOf).Interface()
+
+	files, origins, err := w.load(fresh)
+	if err != nil {
+		// The files of the failed load may be incomplete, so the old ones are kept watched.
+		for path := range w.files {
+			if _, ok := files[path]; !ok {
+				files[path] = stat(path)
+			}
+		}
+		w.files = files
+
+		w.logf("gocfg: failed to reload configuration from %s (%s): %v", w.path, reason, err)
+		w.notify(Event{Path: w.path, Err: err})
+		return
+	}
+	w.files = files
+
+	changes, restart := splitFrozen(Diff(w.current, fresh), w.frozen)
+	if len(restart) > 0 {
+		if w.reject {
+			err = fmt.Errorf("%w: %s", ErrRestartRequired, strings.Join(paths(restart), ", "))
+			w.logf("gocfg: failed to reload configuration from %s (%s): %v", w.path, reason, err)
+			w.notify(Event{Path: w.path, Err: err, Restart: restart})
+			return
+		}
+
+		restoreFrozen(fresh, w.current, restart)
+		restoreOrigins(origins, w.origins, restart)
+		w.logf("gocfg: configuration from %s (%s) changes fields that require a restart: %s",
+			w.path, reason, strings.Join(paths(restart), ", "))
+	}
+	w.current, w.origins = fresh, origins
+
+	w.publish(fresh, explain(w.typeOf, origins))
+	w.logf("gocfg: configuration reloaded from %s (%s): %s", w.path, reason, describe(changes))
+	w.notify(Event{Path: w.path, Changes: changes, Restart: restart})
+}
+
+// changed is a helper method that reports whether any of the files changed since they
+// were read.
+func (w *watcher) changed() bool {
+	for path, state := range w.files {
+		if stat(path) != state {
+			return true
+		}
+	}
+	return false
+}
+
+// logf is a helper method that writes the message to the logger, if any.
+func (w *watcher) logf(format string, args ...any) {
+	if w.logger != nil {
+		w.logger.Printf(format, args...)
+	}
+}
+
+// describe is a helper function that lists the paths of the changed fields for the logs.
+// The values are left out, since they may hold secrets.
+func describe(changes []Change) string {
+	if len(changes) == 0 {
+		return "nothing changed"
+	}
+
+	return "changed " + strings.Join(paths(changes), ", ")
+}
+
+// notify is a helper method that calls the callback, if any.
+func (w *watcher) notify(event Event) {
+	if w.onChange != nil {
+		w.onChange(event)
+	}
+}
+
+// stat is a helper function that returns the current state of the file.
+func stat(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
@@ -0,0 +1,228 @@
+package reload
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path"
+	rf "reflect"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+)
+
+func Test_Watch(t *testing.T) {
+	dir := t.TempDir()
+	filePath := path.Join(dir, "cfg.yaml")
+	includePath := path.Join(dir, "include.yaml")
+	assert.NoError(t, os.WriteFile(includePath, []byte("port: 8080\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filePath, []byte("$include: [include.yaml]\nfield: first\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan Event, 1)
+	structPtr := &loadStruct{}
+	err := Watch(ctx, filePath, structPtr, func(event Event) { events <- event }, func(o *options.Options) {
+		o.Sources = []string{SourceFile}
+		o.PollInterval = 10 * time.Millisecond
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &loadStruct{Field: "first", Port: 8080}, structPtr)
+
+	tests := []struct {
+		name        string
+		path        string
+		data        string
+		wantErr     bool
+		wantChanges []Change
+		wantStruct  *loadStruct
+	}{
+		{
+			name:        "File Changed",
+			path:        filePath,
+			data:        "$include: [include.yaml]\nfield: second\n",
+			wantChanges: []Change{{Path: "Field", Old: "first", New: "second"}},
+			wantStruct:  &loadStruct{Field: "second", Port: 8080},
+		},
+		{
+			name:        "Include Changed",
+			path:        includePath,
+			data:        "port: 9090\n",
+			wantChanges: []Change{{Path: "Port", Old: 8080, New: 9090}},
+			wantStruct:  &loadStruct{Field: "second", Port: 9090},
+		},
+		{
+			name:       "Invalid Keeps Previous",
+			path:       includePath,
+			data:       "port: -1\n",
+			wantErr:    true,
+			wantStruct: &loadStruct{Field: "second", Port: 9090},
+		},
+		{
+			name:       "Broken Keeps Previous",
+			path:       filePath,
+			data:       "field: [\n",
+			wantErr:    true,
+			wantStruct: &loadStruct{Field: "second", Port: 9090},
+		},
+		{
+			name: "Fixed",
+			path: filePath,
+			data: "field: fixed\n",
+			wantChanges: []Change{
+				{Path: "Field", Old: "second", New: "fixed"},
+				{Path: "Port", Old: 9090, New: 80},
+			},
+			wantStruct: &loadStruct{Field: "fixed", Port: 80},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replaceFile(t, tt.path, tt.data)
+
+			select {
+			case event := <-events:
+				assert.Equal(t, filePath, event.Path)
+				assert.Equal(t, tt.wantErr, event.Err != nil)
+				assert.Equal(t, tt.wantChanges, event.Changes)
+			case <-time.After(5 * time.Second):
+				t.Fatal("no reload event")
+			}
+			assert.Equal(t, tt.wantStruct, structPtr)
+		})
+	}
+}
+
+func Test_Watch_Error(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "cfg.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte("port: -1\n"), 0o600))
+
+	structPtr := &loadStruct{}
+	err := Watch(context.Background(), filePath, structPtr, nil, func(o *options.Options) {
+		o.Sources = []string{SourceFile}
+	})
+	assert.EqualError(t, err, "invalid configuration: port must not be negative")
+
+	err = Watch(context.Background(), filePath, "not-a-pointer", nil)
+	assert.Error(t, err)
+}
+
+func Test_Publish(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "cfg.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte("field: first\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	published := make(chan *loadStruct, 2)
+	err := Publish(ctx, filePath, rf.TypeOf(loadStruct{}), func(structPtr any, _ Provenance) {
+		published <- structPtr.(*loadStruct)
+	}, nil, func(o *options.Options) {
+		o.Sources = []string{SourceFile}
+		o.PollInterval = 10 * time.Millisecond
+	})
+	assert.NoError(t, err)
+
+	first := <-published
+	assert.Equal(t, &loadStruct{Field: "first", Port: 80}, first)
+
+	replaceFile(t, filePath, "field: second\n")
+	select {
+	case second := <-published:
+		assert.Equal(t, &loadStruct{Field: "second", Port: 80}, second)
+		assert.Equal(t, &loadStruct{Field: "first", Port: 80}, first)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no published configuration")
+	}
+
+	err = Publish(ctx, filePath, rf.TypeOf(""), func(any, Provenance) {}, nil)
+	assert.Error(t, err)
+}
+
+func Test_Watch_Signal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported on windows")
+	}
+
+	filePath := path.Join(t.TempDir(), "cfg.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte("field: first\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var logs bytes.Buffer
+	events := make(chan Event, 1)
+	structPtr := &loadStruct{}
+	err := Watch(ctx, filePath, structPtr, func(event Event) { events <- event }, func(o *options.Options) {
+		o.Sources = []string{SourceFile}
+		o.PollInterval = -1
+		o.ReloadSignals = []os.Signal{syscall.SIGHUP}
+		o.Logger = log.New(&logs, "", 0)
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filePath, []byte("field: second\n"), 0o600))
+	select {
+	case <-events:
+		t.Fatal("reloaded without a signal")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, &loadStruct{Field: "first", Port: 80}, structPtr)
+
+	process, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		data       string
+		wantLog    string
+		wantStruct *loadStruct
+	}{
+		{
+			name:       "Reloaded",
+			data:       "field: second\n",
+			wantLog:    "gocfg: configuration reloaded from " + filePath + " (signal hangup): changed Field\n",
+			wantStruct: &loadStruct{Field: "second", Port: 80},
+		},
+		{
+			name:       "Failed",
+			data:       "port: -1\n",
+			wantLog:    "gocfg: failed to reload configuration from " + filePath + " (signal hangup): invalid configuration: port must not be negative\n",
+			wantStruct: &loadStruct{Field: "second", Port: 80},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			assert.NoError(t, os.WriteFile(filePath, []byte(tt.data), 0o600))
+			assert.NoError(t, process.Signal(syscall.SIGHUP))
+
+			select {
+			case <-events:
+			case <-time.After(5 * time.Second):
+				t.Fatal("no reload event")
+			}
+			assert.Equal(t, tt.wantLog, logs.String())
+			assert.Equal(t, tt.wantStruct, structPtr)
+		})
+	}
+}
+
+// replaceFile is a helper function that replaces the content of the watched file at once,
+// so the watcher never reads a partially written file.
+func replaceFile(t *testing.T, filePath, data string) {
+	t.Helper()
+
+	tmpPath := filePath + ".tmp"
+	assert.NoError(t, os.WriteFile(tmpPath, []byte(data), 0o600))
+	assert.NoError(t, os.Rename(tmpPath, filePath))
+}
@@ -0,0 +1,8 @@
+package schema
+
+import "fmt"
+
+var (
+	// ErrInvalidRule is returned when the validate tag of a field cannot be parsed
+	ErrInvalidRule = fmt.Errorf("invalid validation rule")
+)
@@ -0,0 +1,57 @@
+package schema
+
+import (
+	"fmt"
+	rf "reflect"
+	"strconv"
+	"strings"
+)
+
+// tag is the struct tag that holds the validation rules of a field, e.g.
+// validate:"required,min=1,max=65535" or validate:"oneof=dev prod".
+const tag = "validate"
+
+// Rules represents the validation rules of a field.
+type Rules struct {
+	Required bool     // The key must be present.
+	Min      *float64 // The lower bound of a number, or of the length of a string, a slice or a map.
+	Max      *float64 // The upper bound of a number, or of the length of a string, a slice or a map.
+	OneOf    []string // The values the field may have, separated by spaces in the tag.
+}
+
+// ParseRules is a function that parses the validate tag of the field. The rules the schema
+// has no keyword for, e.g. email or gte=1, are skipped, so the tag may be shared with a
+// validation library, and so are the rules after dive, which apply to the elements. It
+// returns ErrInvalidRule for bounds that are not numbers.
+func ParseRules(field rf.StructField) (Rules, error) {
+	var result Rules
+
+	value := field.Tag.Get(tag)
+	if value == "" {
+		return result, nil
+	}
+
+	for _, rule := range strings.Split(value, ",") {
+		name, argument, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			result.Required = true
+		case "min", "max":
+			bound, err := strconv.ParseFloat(argument, 64)
+			if err != nil {
+				return Rules{}, fmt.Errorf("%w: %s: %q", ErrInvalidRule, field.Name, rule)
+			}
+			if name == "min" {
+				result.Min = &bound
+			} else {
+				result.Max = &bound
+			}
+		case "oneof":
+			result.OneOf = strings.Fields(argument)
+		case "dive":
+			return result, nil
+		}
+	}
+
+	return result, nil
+}
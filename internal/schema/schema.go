@@ -0,0 +1,324 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	rf "reflect"
+	"strconv"
+	"time"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+// Draft is the URI of the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema represents a JSON Schema of a configuration or of one of its values.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Type        any    `json:"type,omitempty"` // The name of the type, or the names of the types.
+	Description string `json:"description,omitempty"`
+	Default     any    `json:"default,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	WriteOnly   bool   `json:"writeOnly,omitempty"`
+
+	Minimum       *float64 `json:"minimum,omitempty"`
+	Maximum       *float64 `json:"maximum,omitempty"`
+	MinLength     *float64 `json:"minLength,omitempty"`
+	MaxLength     *float64 `json:"maxLength,omitempty"`
+	MinItems      *float64 `json:"minItems,omitempty"`
+	MaxItems      *float64 `json:"maxItems,omitempty"`
+	MinProperties *float64 `json:"minProperties,omitempty"`
+	MaxProperties *float64 `json:"maxProperties,omitempty"`
+
+	Properties           *Properties `json:"properties,omitempty"`
+	Required             []string    `json:"required,omitempty"`
+	AdditionalProperties *Schema     `json:"additionalProperties,omitempty"`
+	Items                *Schema     `json:"items,omitempty"`
+}
+
+// Properties is an ordered mapping of the keys of an object to their schemas.
+type Properties struct {
+	Keys    []string
+	Schemas map[string]*Schema
+}
+
+// Get returns the schema of the key.
+func (p *Properties) Get(key string) (*Schema, bool) {
+	if p == nil {
+		return nil, false
+	}
+	s, ok := p.Schemas[key]
+	return s, ok
+}
+
+// set is a helper method that sets the schema of the key, keeping the order of the keys.
+func (p *Properties) set(key string, s *Schema) {
+	if _, ok := p.Schemas[key]; !ok {
+		p.Keys = append(p.Keys, key)
+	}
+	p.Schemas[key] = s
+}
+
+// MarshalJSON implements json.Marshaler and writes the keys in their order.
+func (p *Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range p.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		keyData, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueData, err := json.Marshal(p.Schemas[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", key, err)
+		}
+
+		buf.Write(keyData)
+		buf.WriteByte(':')
+		buf.Write(valueData)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Marshal returns the schema as indented JSON.
+func (s *Schema) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Generate is a function that generates the JSON Schema of the YAML configuration files of
+// the struct pointed to by structPtr, with the keys the file reader reads under the naming
+// convention of the options, see New. The function returns an error if the struct is not
+// valid or a validate tag cannot be parsed.
+func Generate(structPtr any, opts ...options.Option) ([]byte, error) {
+	if err := reflect.Validation(structPtr); err != nil {
+		return nil, fmt.Errorf("error validating struct: %w", err)
+	}
+
+	s, err := New(rf.TypeOf(structPtr), "yaml", options.New(opts...).Naming)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate schema: %w", err)
+	}
+	return s.Marshal()
+}
+
+// New is a function that generates the schema of the configuration files of the struct type
+// whose format maps keys to fields with the given tag, e.g. yaml. The keys are the same the
+// file reader reads. The types of the values follow the kinds of the fields, nested structs
+// are nested objects, and the default, description and validate tags of the fields are
+// mapped to the default, description, required, enum and bound keywords. The defaults of the
+// fields that hold secrets are left out. The function returns ErrInvalidRule if a validate
+// tag cannot be parsed, see ParseRules.
+func New(typeOf rf.Type, tag string, naming func(string) string) (*Schema, error) {
+	for typeOf.Kind() == rf.Ptr {
+		typeOf = typeOf.Elem()
+	}
+
+	result := newObject()
+	if err := build(result, typeOf, tag, naming, secret.Fields(typeOf), ""); err != nil {
+		return nil, err
+	}
+
+	result.Schema = Draft
+	return result, nil
+}
+
+// newObject is a helper function that returns the schema of an object without properties.
+func newObject() *Schema {
+	return &Schema{Type: "object", Properties: &Properties{Schemas: map[string]*Schema{}}}
+}
+
+// build is a helper function used by New that adds the fields of the struct type to the
+// properties of the object. The prefix parameter is used to build the fully qualified
+// names of the fields, which the secrets are keyed by.
+func build(
+	object *Schema,
+	typeOf rf.Type,
+	tag string,
+	naming func(string) string,
+	secrets map[string]bool,
+	prefix string,
+) error {
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if field.Tag.Get(tag) == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+
+		name := prefix + field.Name
+		fieldType := field.Type
+		for fieldType.Kind() == rf.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && fieldType.Kind() == rf.Struct && reflect.Segment(field, nil) == "" && field.Tag.Get(tag) == "" {
+			if err := build(object, fieldType, tag, naming, secrets, name+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rules, err := ParseRules(field)
+		if err != nil {
+			return err
+		}
+
+		var value *Schema
+		if fieldType.Kind() == rf.Struct && !reflect.IsText(fieldType) {
+			value = newObject()
+			if err = build(value, fieldType, tag, naming, secrets, name+"."); err != nil {
+				return err
+			}
+		} else {
+			if value, err = valueSchema(fieldType, tag); err != nil {
+				return err
+			}
+			if defaultValue := field.Tag.Get("default"); defaultValue != "" && !secrets[name] {
+				value.Default = typed(fieldType, defaultValue)
+			}
+		}
+
+		value.Description = field.Tag.Get("description")
+		value.WriteOnly = secrets[name]
+		applyRules(value, fieldType, rules)
+
+		parent, keys := object, reflect.FileKey(field, tag, naming)
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := parent.Properties.Get(key)
+			if !ok || child.Properties == nil {
+				child = newObject()
+				parent.Properties.set(key, child)
+			}
+			parent = child
+		}
+
+		key := keys[len(keys)-1]
+		parent.Properties.set(key, value)
+		if rules.Required {
+			parent.Required = append(parent.Required, key)
+		}
+	}
+
+	return nil
+}
+
+// valueSchema is a helper function used by build that returns the schema of a value of
+// the type, which is not a nested struct.
+func valueSchema(typeOf rf.Type, tag string) (*Schema, error) {
+	if typeOf == rf.TypeOf(time.Duration(0)) {
+		if tag == "yaml" || tag == "toml" {
+			return &Schema{Type: []string{"string", "integer"}}, nil
+		}
+		return &Schema{Type: "integer"}, nil
+	}
+
+	if reflect.IsText(typeOf) {
+		return &Schema{Type: "string"}, nil
+	}
+
+	switch typeOf.Kind() {
+	case rf.String:
+		return &Schema{Type: "string"}, nil
+	case rf.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case rf.Int, rf.Int8, rf.Int16, rf.Int32, rf.Int64:
+		return &Schema{Type: "integer"}, nil
+	case rf.Uint, rf.Uint8, rf.Uint16, rf.Uint32, rf.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}, nil
+	case rf.Float32, rf.Float64:
+		return &Schema{Type: "number"}, nil
+	case rf.Slice, rf.Array:
+		if typeOf.Elem().Kind() == rf.Uint8 {
+			return &Schema{Type: "string"}, nil
+		}
+		items, err := elemSchema(typeOf.Elem(), tag)
+		return &Schema{Type: "array", Items: items}, err
+	case rf.Map:
+		values, err := elemSchema(typeOf.Elem(), tag)
+		return &Schema{Type: "object", AdditionalProperties: values}, err
+	default:
+		return &Schema{}, nil
+	}
+}
+
+// elemSchema is a helper function used by valueSchema that returns the schema of the
+// elements of a slice or a map.
+func elemSchema(typeOf rf.Type, tag string) (*Schema, error) {
+	for typeOf.Kind() == rf.Ptr {
+		typeOf = typeOf.Elem()
+	}
+
+	if typeOf.Kind() == rf.Struct && !reflect.IsText(typeOf) {
+		result := newObject()
+		if err := build(result, typeOf, tag, nil, secret.Fields(typeOf), ""); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return valueSchema(typeOf, tag)
+}
+
+// applyRules is a helper function used by build that maps the validation rules of a field
+// to the keywords of its schema. The bounds apply to numbers, to the lengths of strings
+// and to the sizes of arrays and objects.
+func applyRules(s *Schema, typeOf rf.Type, rules Rules) {
+	for _, value := range rules.OneOf {
+		s.Enum = append(s.Enum, typed(typeOf, value))
+	}
+
+	if rules.Min == nil && rules.Max == nil {
+		return
+	}
+
+	switch s.Type {
+	case "string":
+		s.MinLength, s.MaxLength = rules.Min, rules.Max
+	case "array":
+		s.MinItems, s.MaxItems = rules.Min, rules.Max
+	case "object":
+		s.MinProperties, s.MaxProperties = rules.Min, rules.Max
+	default:
+		if rules.Min != nil {
+			s.Minimum = rules.Min
+		}
+		s.Maximum = rules.Max
+	}
+}
+
+// typed is a helper function that converts the value of a tag to the JSON type of the
+// field, e.g. the default:"8080" tag of an int field to 8080. Values that cannot be
+// converted are returned as strings.
+func typed(typeOf rf.Type, value string) any {
+	switch typeOf.Kind() {
+	case rf.Bool:
+		if result, err := strconv.ParseBool(value); err == nil {
+			return result
+		}
+	case rf.Int, rf.Int8, rf.Int16, rf.Int32, rf.Int64:
+		if result, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return result
+		}
+	case rf.Uint, rf.Uint8, rf.Uint16, rf.Uint32, rf.Uint64:
+		if result, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return result
+		}
+	case rf.Float32, rf.Float64:
+		if result, err := strconv.ParseFloat(value, 64); err == nil {
+			return result
+		}
+	}
+	return value
+}
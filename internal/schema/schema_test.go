@@ -0,0 +1,236 @@
+package schema
+
+import (
+	rf "reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+type schemaStructNested struct {
+	Host    string        `yaml:"host" default:"localhost"`
+	Port    uint16        `yaml:"port" default:"8080" validate:"required,min=1,max=65535" description:"HTTP port"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type schemaStruct struct {
+	Mode     string             `yaml:"mode" default:"dev" validate:"oneof=dev prod"`
+	Tags     []string           `yaml:"tags" validate:"min=1"`
+	Labels   map[string]int     `yaml:"labels"`
+	Ratio    float64            `yaml:"ratio" validate:"max=1"`
+	Debug    bool               `yaml:"debug" default:"true"`
+	HTTP     schemaStructNested `yaml:"http" validate:"required" description:"HTTP server"`
+	Password secret.Secret      `yaml:"password" default:"hunter2"`
+	Started  time.Time          `yaml:"started"`
+	Skipped  string             `yaml:"-"`
+	private  string
+}
+
+func Test_New(t *testing.T) {
+	s, err := New(rf.TypeOf(&schemaStruct{}), "yaml", nil)
+	assert.NoError(t, err)
+
+	data, err := s.Marshal()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"mode": {"type": "string", "default": "dev", "enum": ["dev", "prod"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1},
+			"labels": {"type": "object", "additionalProperties": {"type": "integer"}},
+			"ratio": {"type": "number", "maximum": 1},
+			"debug": {"type": "boolean", "default": true},
+			"http": {
+				"type": "object",
+				"description": "HTTP server",
+				"properties": {
+					"host": {"type": "string", "default": "localhost"},
+					"port": {"type": "integer", "description": "HTTP port", "default": 8080, "minimum": 1, "maximum": 65535},
+					"timeout": {"type": ["string", "integer"]}
+				},
+				"required": ["port"]
+			},
+			"password": {"type": "string", "writeOnly": true},
+			"started": {"type": "string"}
+		},
+		"required": ["http"]
+	}`, string(data))
+
+	assert.Equal(t, []string{"mode", "tags", "labels", "ratio", "debug", "http", "password", "started"}, s.Properties.Keys)
+}
+
+func Test_Generate(t *testing.T) {
+	type config struct {
+		HTTPPort int `validate:"required"`
+	}
+
+	data, err := Generate(&config{}, func(o *options.Options) { o.Naming = reflect.SnakeCase })
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {"http_port": {"type": "integer"}},
+		"required": ["http_port"]
+	}`, string(data))
+
+	_, err = Generate(config{})
+	assert.ErrorIs(t, err, reflect.ErrNotPointer)
+}
+
+func Test_New_Keys(t *testing.T) {
+	type config struct {
+		HTTPPort int           `cfg:"http.port"`
+		Timeout  time.Duration `json:"timeout"`
+	}
+
+	tests := []struct {
+		name    string
+		tag     string
+		naming  func(string) string
+		wantKey []string
+		want    *Schema
+	}{
+		{
+			name:    "Canonical",
+			tag:     "json",
+			naming:  reflect.SnakeCase,
+			wantKey: []string{"http", "port"},
+			want:    &Schema{Type: "integer"},
+		},
+		{
+			name:    "Duration JSON",
+			tag:     "json",
+			wantKey: []string{"timeout"},
+			want:    &Schema{Type: "integer"},
+		},
+		{
+			name:    "Duration TOML",
+			tag:     "toml",
+			wantKey: []string{"Timeout"},
+			want:    &Schema{Type: []string{"string", "integer"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(rf.TypeOf(&config{}), tt.tag, tt.naming)
+			assert.NoError(t, err)
+
+			for _, key := range tt.wantKey {
+				var ok bool
+				s, ok = s.Properties.Get(key)
+				assert.True(t, ok, key)
+			}
+			assert.Equal(t, tt.want, s)
+		})
+	}
+}
+
+func Test_ParseRules(t *testing.T) {
+	one, ten := 1.0, 10.0
+
+	tests := []struct {
+		name    string
+		tag     rf.StructTag
+		want    Rules
+		wantErr error
+	}{
+		{name: "Empty", tag: ``, want: Rules{}},
+		{name: "Required", tag: `validate:"required"`, want: Rules{Required: true}},
+		{name: "Bounds", tag: `validate:"min=1, max=10"`, want: Rules{Min: &one, Max: &ten}},
+		{name: "OneOf", tag: `validate:"oneof=dev prod"`, want: Rules{OneOf: []string{"dev", "prod"}}},
+		{name: "Invalid Bound", tag: `validate:"min=one"`, wantErr: ErrInvalidRule},
+		{name: "Unknown", tag: `validate:"required,email,gte=1"`, want: Rules{Required: true}},
+		{name: "Dive", tag: `validate:"min=1,dive,min=10"`, want: Rules{Min: &one}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRules(rf.StructField{Name: "Field", Tag: tt.tag})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_New_InvalidRule(t *testing.T) {
+	type config struct {
+		Items []struct {
+			Name string `validate:"min=one"`
+		}
+	}
+
+	_, err := New(rf.TypeOf(&config{}), "yaml", nil)
+	assert.ErrorIs(t, err, ErrInvalidRule)
+}
+
+func Test_Schema_Validate(t *testing.T) {
+	s, err := New(rf.TypeOf(&schemaStruct{}), "yaml", nil)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		document any
+		fold     bool
+		want     map[string]string
+	}{
+		{
+			name: "Valid",
+			document: map[string]any{
+				"mode": "prod", "tags": []any{"a"}, "ratio": 0.5, "debug": nil,
+				"http": map[string]any{"port": 8080, "timeout": "1m30s"}, "unknown": 1,
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "Violations",
+			document: map[string]any{
+				"tags":     []any{},
+				"labels":   map[string]any{"a": "b"},
+				"ratio":    2,
+				"http":     map[string]any{"port": 70000.0, "host": true},
+				"password": 1,
+			},
+			want: map[string]string{
+				"tags":      "number of items must be at least 1, got 0",
+				"labels.a":  `expected integer, got string "b"`,
+				"ratio":     "must be at most 1, got integer 2",
+				"http.port": "must be at most 65535, got number 70000",
+				"http.host": "expected string, got boolean true",
+				"password":  "expected string, got integer",
+			},
+		},
+		{
+			name:     "Case Sensitive",
+			document: map[string]any{"HTTP": map[string]any{"Port": "x"}},
+			want:     map[string]string{},
+		},
+		{
+			name:     "Fold",
+			document: map[string]any{"HTTP": map[string]any{"Port": "x"}},
+			fold:     true,
+			want:     map[string]string{"HTTP.Port": `expected integer, got string "x"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			for _, v := range s.Validate(tt.document, tt.fold) {
+				got[v.Key()] = v.Message
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	rf "reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violation describes a value of a document that does not match its schema.
+type Violation struct {
+	Path    []string // The keys of the value, with the indexes of the arrays written as [i].
+	Message string   // What is wrong with the value, e.g. expected integer, got string "80a0".
+}
+
+// Key returns the path of the value as a dotted key, e.g. http.port or tags[0].
+func (v Violation) Key() string {
+	var b strings.Builder
+	for i, key := range v.Path {
+		if i > 0 && !strings.HasPrefix(key, "[") {
+			b.WriteByte('.')
+		}
+		b.WriteString(key)
+	}
+	return b.String()
+}
+
+// Validate checks the values of the document, e.g. a decoded configuration file, against
+// the schema: their types, enums and bounds. Keys without a schema and null values, which
+// the decoders skip, are not checked, and the keys of the document also match the properties
+// case-insensitively if fold is set. The required keywords are not checked either, since a
+// configuration file may leave the values to the other files and sources. The values of the
+// write-only schemas are not quoted in the messages.
+func (s *Schema) Validate(document any, fold bool) []Violation {
+	var result []Violation
+	s.validate(document, fold, nil, &result)
+	return result
+}
+
+// validate is a helper method for Validate. The path parameter holds the keys of the value.
+func (s *Schema) validate(value any, fold bool, path []string, result *[]Violation) {
+	report := func(format string, args ...any) {
+		*result = append(*result, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	kind := kindOf(value)
+	if kind == "null" {
+		return
+	}
+
+	if !s.accepts(value, kind) {
+		report("expected %s, got %s", strings.Join(s.types(), " or "), s.describe(value, kind))
+		return
+	}
+
+	if len(s.Enum) > 0 && !s.inEnum(value) {
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			values[i] = fmt.Sprint(v)
+		}
+		report("must be one of %s, got %s", strings.Join(values, ", "), s.describe(value, kind))
+		return
+	}
+
+	valueOf := rf.ValueOf(value)
+	switch kind {
+	case "integer", "number":
+		number := toFloat(value)
+		s.checkBounds(number, s.Minimum, s.Maximum, "", s.describe(value, kind), report)
+	case "string":
+		length := utf8.RuneCountInString(fmt.Sprint(value))
+		s.checkBounds(float64(length), s.MinLength, s.MaxLength, "length ", strconv.Itoa(length), report)
+	case "array":
+		s.checkBounds(float64(valueOf.Len()), s.MinItems, s.MaxItems, "number of items ", strconv.Itoa(valueOf.Len()), report)
+		if s.Items != nil {
+			for i := 0; i < valueOf.Len(); i++ {
+				s.Items.validate(valueOf.Index(i).Interface(), fold, appendPath(path, fmt.Sprintf("[%d]", i)), result)
+			}
+		}
+	case "object":
+		s.checkBounds(float64(valueOf.Len()), s.MinProperties, s.MaxProperties, "number of keys ", strconv.Itoa(valueOf.Len()), report)
+
+		keys := valueOf.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+		for _, key := range keys {
+			name := fmt.Sprint(key.Interface())
+			if child := s.property(name, fold); child != nil {
+				child.validate(valueOf.MapIndex(key).Interface(), fold, appendPath(path, name), result)
+			}
+		}
+	}
+}
+
+// checkBounds is a helper method used by validate to report the value outside the bounds.
+func (s *Schema) checkBounds(value float64, minimum, maximum *float64, subject, got string, report func(string, ...any)) {
+	if minimum != nil && value < *minimum {
+		report("%smust be at least %s, got %s", subject, formatNumber(*minimum), got)
+	}
+	if maximum != nil && value > *maximum {
+		report("%smust be at most %s, got %s", subject, formatNumber(*maximum), got)
+	}
+}
+
+// property is a helper method used by validate that returns the schema of the key of an
+// object, or nil if it has none.
+func (s *Schema) property(key string, fold bool) *Schema {
+	if child, ok := s.Properties.Get(key); ok {
+		return child
+	}
+
+	if fold && s.Properties != nil {
+		for _, name := range s.Properties.Keys {
+			if strings.EqualFold(name, key) {
+				return s.Properties.Schemas[name]
+			}
+		}
+	}
+
+	return s.AdditionalProperties
+}
+
+// types is a helper method that returns the names of the types the schema accepts.
+func (s *Schema) types() []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	default:
+		return nil
+	}
+}
+
+// accepts is a helper method used by validate that reports whether the schema accepts the
+// kind of the value. Numbers without a fractional part are integers.
+func (s *Schema) accepts(value any, kind string) bool {
+	types := s.types()
+	if len(types) == 0 {
+		return true
+	}
+
+	for _, t := range types {
+		if t == kind || t == "number" && kind == "integer" {
+			return true
+		}
+		if t == "integer" && kind == "number" {
+			if number := toFloat(value); number == float64(int64(number)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inEnum is a helper method used by validate that reports whether the value is one of the
+// values of the enum.
+func (s *Schema) inEnum(value any) bool {
+	for _, v := range s.Enum {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// describe is a helper method used by validate that returns the kind of the value followed
+// by the value itself, which is left out for objects, arrays and write-only schemas.
+func (s *Schema) describe(value any, kind string) string {
+	switch {
+	case s.WriteOnly, kind == "object", kind == "array":
+		return kind
+	case kind == "string":
+		return fmt.Sprintf("%s %q", kind, fmt.Sprint(value))
+	default:
+		return fmt.Sprintf("%s %v", kind, value)
+	}
+}
+
+// kindOf is a helper function that returns the JSON type of a decoded value. Values the
+// decoders produce for dates and times are strings.
+func kindOf(value any) string {
+	if value == nil {
+		return "null"
+	}
+
+	if number, ok := value.(json.Number); ok {
+		if _, err := number.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	}
+
+	switch rf.ValueOf(value).Kind() {
+	case rf.Bool:
+		return "boolean"
+	case rf.Int, rf.Int8, rf.Int16, rf.Int32, rf.Int64, rf.Uint, rf.Uint8, rf.Uint16, rf.Uint32, rf.Uint64:
+		return "integer"
+	case rf.Float32, rf.Float64:
+		return "number"
+	case rf.Slice, rf.Array:
+		return "array"
+	case rf.Map:
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+// toFloat is a helper function that returns the decoded number as a float.
+func toFloat(value any) float64 {
+	if number, ok := value.(json.Number); ok {
+		result, _ := number.Float64()
+		return result
+	}
+
+	valueOf := rf.ValueOf(value)
+	switch valueOf.Kind() {
+	case rf.Int, rf.Int8, rf.Int16, rf.Int32, rf.Int64:
+		return float64(valueOf.Int())
+	case rf.Uint, rf.Uint8, rf.Uint16, rf.Uint32, rf.Uint64:
+		return float64(valueOf.Uint())
+	case rf.Float32, rf.Float64:
+		return valueOf.Float()
+	default:
+		return 0
+	}
+}
+
+// formatNumber is a helper function that formats the bound without trailing zeros.
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+// appendPath is a helper function that returns a copy of the path with the key appended.
+func appendPath(path []string, key string) []string {
+	return append(path[:len(path):len(path)], key)
+}
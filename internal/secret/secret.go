@@ -0,0 +1,190 @@
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	rf "reflect"
+	"strings"
+
+	"github.com/dsbasko/go-cfg/internal/reflect"
+)
+
+// Redacted replaces the values of the secrets wherever they are printed.
+const Redacted = "[REDACTED]"
+
+// tag is the struct tag that marks the fields holding secrets with the secret:"true" value.
+const tag = "secret"
+
+// Secret is a string that holds a secret, e.g. a password. It is read from the configuration
+// sources like any other string, but it prints as Redacted, so it does not leak into logs.
+// Reveal returns the actual value.
+type Secret string
+
+// Reveal returns the actual value of the secret.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// String implements fmt.Stringer and returns Redacted.
+func (s Secret) String() string {
+	return Redacted
+}
+
+// GoString implements fmt.GoStringer and returns Redacted, so the %#v verb does not reveal
+// the secret either.
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", Redacted)
+}
+
+// MarshalText implements encoding.TextMarshaler and returns Redacted, which the YAML and
+// TOML encoders use.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// MarshalJSON implements json.Marshaler and returns Redacted as a JSON string.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// LogValue implements slog.LogValuer and returns Redacted.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// secretType is the type of Secret.
+var secretType = rf.TypeOf(Secret(""))
+
+// Fields is a function that returns the fully qualified names of the fields of the struct
+// type that hold secrets: the fields of type Secret and the fields tagged with secret:"true".
+// The fields of a nested struct tagged with secret:"true" hold secrets as well.
+func Fields(typeOf rf.Type) map[string]bool {
+	for typeOf.Kind() == rf.Ptr {
+		typeOf = typeOf.Elem()
+	}
+
+	result := map[string]bool{}
+	fieldsRecursive(typeOf, "", false, result)
+	return result
+}
+
+// fieldsRecursive is a helper function for Fields. The secret parameter reports whether
+// the struct itself is tagged as a secret.
+func fieldsRecursive(typeOf rf.Type, prefix string, secret bool, result map[string]bool) {
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		path := fmt.Sprintf("%s%s", prefix, field.Name)
+		fieldSecret := secret || field.Tag.Get(tag) == "true"
+
+		if field.Type.Kind() == rf.Struct {
+			fieldsRecursive(field.Type, path+".", fieldSecret, result)
+			continue
+		}
+
+		if fieldSecret || field.Type == secretType {
+			result[path] = true
+		}
+	}
+}
+
+// Mask returns the value wrapped in Secret, so printing it does not reveal it. Values that
+// already are secrets are returned as they are.
+func Mask(value any) any {
+	if s, ok := value.(Secret); ok {
+		return s
+	}
+	return Secret(fmt.Sprint(value))
+}
+
+// Reveal returns the actual value of a Secret, or the value as it is otherwise.
+func Reveal(value any) any {
+	if s, ok := value.(Secret); ok {
+		return s.Reveal()
+	}
+	return value
+}
+
+// redactedError is an error whose message has the values of the secrets replaced.
+type redactedError struct {
+	err     error
+	message string
+}
+
+// Error returns the message with the values of the secrets replaced.
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the original error, so errors.Is and errors.As keep working.
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// RedactError is a function that returns the error with every occurrence of the values in
+// its message replaced with Redacted. The original error is kept for errors.Is and errors.As.
+// It returns the error as it is if the message contains none of the values.
+func RedactError(err error, values []string) error {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+	for _, value := range values {
+		if value != "" {
+			message = strings.ReplaceAll(message, value, Redacted)
+		}
+	}
+
+	if message == err.Error() {
+		return err
+	}
+	return &redactedError{err: err, message: message}
+}
+
+// Values is a function that returns the current values of the fields of the struct that hold
+// secrets, skipping the empty ones. They are used to redact the error messages.
+func Values(structPtr any) []string {
+	valueOf := rf.ValueOf(structPtr)
+	for valueOf.Kind() == rf.Ptr {
+		if valueOf.IsNil() {
+			return nil
+		}
+		valueOf = valueOf.Elem()
+	}
+	if valueOf.Kind() != rf.Struct {
+		return nil
+	}
+
+	var result []string
+	for path := range Fields(valueOf.Type()) {
+		field := valueOf
+		for _, name := range strings.Split(path, ".") {
+			field = field.FieldByName(name)
+		}
+
+		if field.CanInterface() && !field.IsZero() {
+			result = append(result, fmt.Sprint(Reveal(field.Interface())))
+		}
+	}
+	return result
+}
+
+// EnvValues is a function that returns the values of the environment variables of the fields
+// of the struct that hold secrets, skipping the unset and empty ones. The names of the
+// variables are derived with the naming function the same way the readers do.
+func EnvValues(structPtr any, naming func(string) string) []string {
+	secrets := Fields(rf.TypeOf(structPtr))
+
+	var result []string
+	for field, keys := range reflect.FieldKeys(structPtr, naming) {
+		if !secrets[field] || keys.Env == "" {
+			continue
+		}
+		if value := os.Getenv(keys.Env); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	rf "reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Secret(t *testing.T) {
+	type InStruct struct {
+		Password Secret `json:"password" yaml:"password"`
+	}
+	structPtr := &InStruct{Password: "hunter2"}
+
+	assert.Equal(t, "hunter2", structPtr.Password.Reveal())
+	assert.Equal(t, "[REDACTED]", fmt.Sprint(structPtr.Password))
+	assert.Equal(t, `&{[REDACTED]}`, fmt.Sprintf("%v", structPtr))
+	assert.Equal(t, `&secret.InStruct{Password:"[REDACTED]"}`, fmt.Sprintf("%#v", structPtr))
+
+	data, err := json.Marshal(structPtr)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"password":"[REDACTED]"}`, string(data))
+
+	data, err = yaml.Marshal(structPtr)
+	assert.NoError(t, err)
+	assert.Equal(t, "password: '[REDACTED]'\n", string(data))
+
+	var logs bytes.Buffer
+	slog.New(slog.NewTextHandler(&logs, nil)).Info("loaded", "password", structPtr.Password)
+	assert.Contains(t, logs.String(), "password=[REDACTED]")
+	assert.NotContains(t, logs.String(), "hunter2")
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"password":"swordfish"}`), structPtr))
+	assert.Equal(t, Secret("swordfish"), structPtr.Password)
+}
+
+func Test_Fields(t *testing.T) {
+	type InStructNested struct {
+		User     string
+		Password string
+	}
+	type InStruct struct {
+		Token    Secret
+		Key      string `secret:"true"`
+		Name     string
+		Database InStructNested `secret:"true"`
+		Cache    InStructNested
+	}
+
+	assert.Equal(t, map[string]bool{
+		"Token":             true,
+		"Key":               true,
+		"Database.User":     true,
+		"Database.Password": true,
+	}, Fields(rf.TypeOf(&InStruct{})))
+}
+
+func Test_Values(t *testing.T) {
+	type InStruct struct {
+		Token Secret
+		Port  int `secret:"true"`
+		Empty Secret
+		Name  string
+	}
+
+	assert.ElementsMatch(t, []string{"hunter2", "8080"}, Values(&InStruct{Token: "hunter2", Port: 8080, Name: "app"}))
+	assert.Nil(t, Values((*InStruct)(nil)))
+}
+
+func Test_EnvValues(t *testing.T) {
+	type InStruct struct {
+		Token Secret `env:"SECRET_TOKEN"`
+		Key   string `cfg:"secret.key" secret:"true"`
+		Name  string `env:"SECRET_NAME"`
+		Unset Secret `env:"SECRET_UNSET"`
+	}
+	t.Setenv("SECRET_TOKEN", "hunter2")
+	t.Setenv("SECRET_KEY", "swordfish")
+	t.Setenv("SECRET_NAME", "app")
+
+	assert.ElementsMatch(t, []string{"hunter2", "swordfish"}, EnvValues(&InStruct{}, nil))
+}
+
+func Test_RedactError(t *testing.T) {
+	errBase := errors.New("base error")
+
+	tests := []struct {
+		name    string
+		err     error
+		values  []string
+		wantErr string
+	}{
+		{
+			name:    "Redacted",
+			err:     fmt.Errorf("parsing %q: %w", "hunter2", errBase),
+			values:  []string{"hunter2", ""},
+			wantErr: `parsing "[REDACTED]": base error`,
+		},
+		{
+			name:    "No Secrets",
+			err:     fmt.Errorf("parsing %q: %w", "80", errBase),
+			values:  []string{"hunter2"},
+			wantErr: `parsing "80": base error`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RedactError(tt.err, tt.values)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.ErrorIs(t, err, errBase)
+		})
+	}
+
+	assert.NoError(t, RedactError(nil, []string{"hunter2"}))
+}
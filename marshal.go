@@ -0,0 +1,60 @@
+package gocfg
+
+import (
+	"github.com/dsbasko/go-cfg/internal/file"
+)
+
+// ErrUnsupportedFormat is the error of Marshal for a format it cannot write.
+var ErrUnsupportedFormat = file.ErrUnsupportedFormat
+
+// Marshal is a function that encodes the configuration in the provided cfg structure in the format, which is
+// json, yaml, toml or env. The keys are the same the configuration files are read with: the tags of the format,
+// the canonical keys and the naming convention set with WithNaming, so the result can be read back with ReadFile.
+// In the env format every field is written as its environment variable.
+//
+// The values of the secrets, see Secret, are written as "[REDACTED]", unless WithRevealSecrets is used. The
+// function returns ErrUnsupportedFormat for other formats.
+//
+// Example:
+//
+//	cfg := &Config{}
+//	gocfg.MustLoad("config.yaml", cfg)
+//
+//	if *printConfig {
+//		data, err := gocfg.Marshal(cfg, "yaml")
+//		if err != nil {
+//			log.Fatalf("failed to print configuration: %v", err)
+//		}
+//		os.Stdout.Write(data)
+//		os.Exit(0)
+//	}
+func Marshal(cfg any, format string, opts ...Option) ([]byte, error) {
+	return file.Marshal(cfg, format, internalOptions(opts)...)
+}
+
+// GenerateExample is a function that generates an example configuration file, e.g. config.example.yaml or
+// .env.example, for the type of the provided cfg structure in the format, which is json, yaml, toml or env.
+// Every field is written with the value of its default tag, or its zero value, and the description tags of
+// the fields are written as comments, except in json, which has no comments. The keys are the same Marshal
+// writes, so the file can be read back with ReadFile. The values of the cfg structure itself are not used,
+// and the values of the secrets are written as "[REDACTED]", unless WithRevealSecrets is used.
+//
+// Example:
+//
+//	type Config struct {
+//		Mode string `yaml:"mode" env:"MODE" default:"dev" description:"Application mode"`
+//	}
+//
+//	data, err := gocfg.GenerateExample(&Config{}, "yaml")
+//	if err != nil {
+//		log.Fatalf("failed to generate example: %v", err)
+//	}
+//	os.WriteFile("config.example.yaml", data, 0o644)
+//
+// It writes:
+//
+//	# Application mode
+//	mode: dev
+func GenerateExample(cfg any, format string, opts ...Option) ([]byte, error) {
+	return file.Example(cfg, format, internalOptions(opts)...)
+}
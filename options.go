@@ -0,0 +1,266 @@
+package gocfg
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/dsbasko/go-cfg/internal/options"
+	"github.com/dsbasko/go-cfg/internal/reflect"
+	"github.com/dsbasko/go-cfg/internal/reload"
+)
+
+// Option is a function that changes the way the configuration is read.
+// Options are passed as the last arguments of the reading functions.
+type Option func(*options.Options)
+
+// WithStrict makes ReadFile reject keys of the configuration file that do not match any field of the
+// cfg structure. This catches misspelled keys, which are silently ignored otherwise. The error contains
+// the file name, the line and the key, together with a suggestion of the closest known key.
+//
+// Example:
+//
+//	if err := gocfg.ReadFile("config.yaml", &cfg, gocfg.WithStrict()); err != nil {
+//		log.Fatalf("failed to read configuration file: %v", err)
+//	}
+//
+// For the key read_timout in the http section on line 12 of config.yaml it returns the error:
+//
+//	unknown key: config.yaml:12: "http.read_timout" (did you mean "http.read_timeout"?)
+func WithStrict() Option {
+	return func(o *options.Options) {
+		o.Strict = true
+	}
+}
+
+// WithLenientJSON makes ReadFile accept comments, trailing commas, unquoted keys and single-quoted strings
+// in .json files, the same way as in .json5 and .jsonc files, which always accept them.
+//
+// Example:
+//
+//	gocfg.MustReadFile("config.json", &cfg, gocfg.WithLenientJSON())
+func WithLenientJSON() Option {
+	return func(o *options.Options) {
+		o.LenientJSON = true
+	}
+}
+
+// WithSchemaValidation makes ReadFile validate the values of the configuration file against the schema of the
+// cfg structure, see JSONSchema, before decoding it: their types, the oneof values and the min and max bounds of
+// the validate tags. The errors name the key, the problem and the line, instead of the messages of the decoders.
+// The required rule is not checked, since other files and sources may set the values. JSON, JSON5, JSONC,
+// Jsonnet, YAML and TOML files are validated, including the inline sections of the profiles.
+//
+// Example:
+//
+//	if err := gocfg.ReadFile("config.yaml", &cfg, gocfg.WithSchemaValidation()); err != nil {
+//		log.Fatalf("failed to read configuration file: %v", err)
+//	}
+//
+// For the value 80a0 of the port key in the http section on line 12 of config.yaml it returns the error:
+//
+//	schema violation: http.port: expected integer, got string "80a0" (config.yaml:12)
+func WithSchemaValidation() Option {
+	return func(o *options.Options) {
+		o.ValidateSchema = true
+	}
+}
+
+// WithProfile makes ReadFile read the configuration of the profile, e.g. prod, over the base configuration.
+// The section of the profile under the top-level profiles key of the file is read over the file itself,
+// and then the file of the profile, e.g. config.prod.yaml for config.yaml, is read if it exists:
+//
+//	mode: dev
+//	profiles:
+//	  prod:
+//	    mode: prod
+//
+// Example:
+//
+//	gocfg.MustReadFile("config.yaml", &cfg, gocfg.WithProfile("prod"))
+func WithProfile(name string) Option {
+	return func(o *options.Options) {
+		o.Profile = name
+	}
+}
+
+// WithProfileFrom selects the profile of ReadFile, see WithProfile, with the command-line flag or, if the
+// flag is not set, with the environment variable. Either name may be empty. Pass the same option to
+// ReadFlag, so it accepts the flag as well.
+//
+// Example:
+//
+//	opt := gocfg.WithProfileFrom("APP_PROFILE", "profile")
+//	gocfg.MustReadFile("config.yaml", &cfg, opt)
+//	gocfg.MustReadFlag(&cfg, opt)
+//
+// Running the program with APP_PROFILE=prod or --profile=prod reads config.yaml and then config.prod.yaml.
+func WithProfileFrom(envName, flagName string) Option {
+	return func(o *options.Options) {
+		o.ProfileEnv = envName
+		o.ProfileFlag = flagName
+	}
+}
+
+// Source is a source of the configuration read by Load and Watch.
+type Source string
+
+// The sources of the configuration.
+const (
+	SourceFile Source = reload.SourceFile // The configuration file.
+	SourceEnv  Source = reload.SourceEnv  // The environment variables.
+	SourceFlag Source = reload.SourceFlag // The command-line flags.
+)
+
+// WithSources sets the sources Load and Watch read and their order, where each source overrides the values
+// of the previous ones. By default the file is read first, then the environment variables and then the flags.
+//
+// Example:
+//
+//	gocfg.MustLoad("config.yaml", &cfg, gocfg.WithSources(gocfg.SourceEnv, gocfg.SourceFile))
+func WithSources(sources ...Source) Option {
+	return func(o *options.Options) {
+		o.Sources = make([]string, len(sources))
+		for i, source := range sources {
+			o.Sources[i] = string(source)
+		}
+	}
+}
+
+// WithPollInterval sets how often Watch checks the configuration files for changes. The default is one second.
+// A negative interval disables the checks, e.g. to reload the configuration only on signals, see WithReloadSignals.
+func WithPollInterval(interval time.Duration) Option {
+	return func(o *options.Options) {
+		o.PollInterval = interval
+	}
+}
+
+// WithReloadSignals makes Watch reload the configuration every time the process receives one of the signals,
+// whether the files changed or not. The reload runs the full load pipeline, so the environment variables and
+// the flags are read again as well. Without arguments the configuration is reloaded on SIGHUP.
+//
+// Example:
+//
+//	err := gocfg.Watch(ctx, "config.yaml", &cfg, nil,
+//		gocfg.WithReloadSignals(),
+//		gocfg.WithPollInterval(-1),
+//		gocfg.WithLogger(log.Default()),
+//	)
+//
+// Running kill -HUP <pid> then reloads the configuration and logs the result.
+func WithReloadSignals(signals ...os.Signal) Option {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	return func(o *options.Options) {
+		o.ReloadSignals = signals
+	}
+}
+
+// WithFailOnRestartRequired makes Watch reject a reload that changes fields tagged with reload:"false".
+// The reload fails with ErrRestartRequired, which lists the fields, and the previous configuration is kept.
+// Without the option the tagged fields keep their old values, and the other changes are applied.
+func WithFailOnRestartRequired() Option {
+	return func(o *options.Options) {
+		o.RejectFrozen = true
+	}
+}
+
+// WithEncryptionKey sets the base64-encoded key the encrypted values of the configuration files are decrypted
+// with, see Encrypt. It takes precedence over WithEncryptionKeyFile and WithEncryptionKeyEnv.
+func WithEncryptionKey(key string) Option {
+	return func(o *options.Options) {
+		o.EncryptionKey = key
+	}
+}
+
+// WithEncryptionKeyFile reads the base64-encoded key the encrypted values are decrypted with from the file,
+// e.g. a mounted Kubernetes secret. It takes precedence over WithEncryptionKeyEnv.
+func WithEncryptionKeyFile(path string) Option {
+	return func(o *options.Options) {
+		o.EncryptionKeyFile = path
+	}
+}
+
+// WithEncryptionKeyEnv reads the base64-encoded key the encrypted values are decrypted with from the environment
+// variable instead of GOCFG_ENCRYPTION_KEY.
+func WithEncryptionKeyEnv(name string) Option {
+	return func(o *options.Options) {
+		o.EncryptionKeyEnv = name
+	}
+}
+
+// WithRevealSecrets makes Marshal write the actual values of the secrets instead of "[REDACTED]", e.g. to
+// write the effective configuration to a file that is read back later.
+func WithRevealSecrets() Option {
+	return func(o *options.Options) {
+		o.RevealSecrets = true
+	}
+}
+
+// Logger is the interface of the loggers used by Watch, which *log.Logger implements.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// WithLogger makes Watch log the result of every reload, including the error of a failed one, with the logger.
+func WithLogger(logger Logger) Option {
+	return func(o *options.Options) {
+		o.Logger = logger
+	}
+}
+
+// Naming is a naming convention that derives the canonical key of a field from its name.
+type Naming func(fieldName string) string
+
+// SnakeCase is a naming convention that converts ReadTimeout to read_timeout and HTTPPort to http_port.
+func SnakeCase(fieldName string) string {
+	return reflect.SnakeCase(fieldName)
+}
+
+// KebabCase is a naming convention that converts ReadTimeout to read-timeout and HTTPPort to http-port.
+func KebabCase(fieldName string) string {
+	return reflect.KebabCase(fieldName)
+}
+
+// CamelCase is a naming convention that converts ReadTimeout to readTimeout and HTTPPort to httpPort.
+func CamelCase(fieldName string) string {
+	return reflect.CamelCase(fieldName)
+}
+
+// WithNaming derives the canonical keys of the fields without a cfg tag from their names using the
+// naming convention.
+//
+// The canonical key of a field is the single source of its names in every source. It is set with the
+// cfg tag, and nested structs prefix the keys of their fields with their own key:
+//
+//	type Config struct {
+//		HTTP struct {
+//			Port        int `cfg:"port"`
+//			ReadTimeout int `cfg:"read_timeout"`
+//		} `cfg:"http"`
+//	}
+//
+// The Port field above is read from the HTTP_PORT environment variable, the --http-port flag and the
+// port key of the http section of JSON, YAML and TOML files. The env, flag, json, yaml and toml tags
+// still take precedence for their own source. A cfg tag with dots maps a field to a nested key of the
+// configuration files, e.g. cfg:"http.port" on a top-level field.
+//
+// With WithNaming(gocfg.SnakeCase) the same names are derived for a struct without any tags:
+//
+//	gocfg.MustReadEnv(&cfg, gocfg.WithNaming(gocfg.SnakeCase))
+func WithNaming(naming Naming) Option {
+	return func(o *options.Options) {
+		o.Naming = naming
+	}
+}
+
+// internalOptions converts the options into the ones accepted by the internal readers.
+func internalOptions(opts []Option) []options.Option {
+	result := make([]options.Option, len(opts))
+	for i, opt := range opts {
+		result[i] = options.Option(opt)
+	}
+	return result
+}
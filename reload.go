@@ -0,0 +1,107 @@
+package gocfg
+
+import (
+	"context"
+
+	"github.com/dsbasko/go-cfg/internal/reload"
+)
+
+// ErrRestartRequired is the error of a reload that changes fields tagged with reload:"false" when
+// WithFailOnRestartRequired is used.
+var ErrRestartRequired = reload.ErrRestartRequired
+
+// Event describes the result of a reload of the configuration by Watch. Err is nil if the new
+// configuration was loaded and validated, otherwise it holds the error and the configuration is
+// left unchanged. Changes lists the fields whose values the reload changed, and Restart lists the
+// changes of the fields tagged with reload:"false", which only take effect after a restart.
+type Event = reload.Event
+
+// Change describes a field changed by a reload: its fully qualified name, e.g. HTTP.Port, and its
+// old and new values.
+type Change = reload.Change
+
+// OnChange returns a callback for Watch that calls fn only if a reload changed a field in the subtree of
+// the prefix, which is the fully qualified name of a nested struct or a field, e.g. HTTP for HTTP.Port and
+// HTTP.Host. The event passed to fn only holds the changes of the subtree. Failed reloads are not passed on.
+//
+// Example:
+//
+//	err := gocfg.Watch(ctx, "config.yaml", cfg, gocfg.OnChange("Log.Level", func(event gocfg.Event) {
+//		logger.SetLevel(event.Changes[0].New.(string))
+//	}))
+func OnChange(prefix string, fn func(Event)) func(Event) {
+	var subscribers reload.Subscribers
+	subscribers.Add(prefix, fn)
+	return subscribers.Notify
+}
+
+// Load is a function that runs the full load pipeline into the provided cfg structure. It writes the
+// default values, then reads the configuration file at the path, the environment variables and the
+// command-line flags, so the flags take precedence. The order of the sources can be changed with
+// WithSources. Finally, if the cfg structure has a Validate() error method, it is called.
+// The function returns an error if any of these steps fail.
+//
+// Example:
+//
+//	type Config struct {
+//		Mode string `default:"dev" yaml:"mode" env:"MODE" flag:"mode"`
+//	}
+//
+//	func (c *Config) Validate() error {
+//		if c.Mode != "dev" && c.Mode != "prod" {
+//			return fmt.Errorf("unknown mode %q", c.Mode)
+//		}
+//		return nil
+//	}
+//
+//	func main() {
+//		cfg := &Config{}
+//		if err := gocfg.Load("config.yaml", cfg); err != nil {
+//			log.Fatalf("failed to load configuration: %v", err)
+//		}
+//	}
+func Load(path string, cfg any, opts ...Option) error {
+	_, err := reload.Load(path, cfg, internalOptions(opts)...)
+	return err
+}
+
+// MustLoad is similar to Load but panics if the loading process fails.
+func MustLoad(path string, cfg any, opts ...Option) {
+	if err := Load(path, cfg, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// Watch is a function that loads the configuration into the provided cfg structure with Load and reloads
+// it whenever the configuration file, one of the files it includes or the file of the profile changes.
+// The files are polled every second, which can be changed with WithPollInterval, until the context is done.
+//
+// Every reload runs the full load pipeline into a fresh structure. Only when it loads and validates
+// successfully, its content replaces the content of cfg and onChange is called. A failed reload keeps
+// the previous configuration and calls onChange with the error. The function returns an error if the
+// initial load fails.
+//
+// Some settings, e.g. the listen port, cannot change at runtime. Tag them with reload:"false", which also
+// covers all the fields of a nested struct, and they keep the values of the first load. A reload that
+// changes them still succeeds, but lists their changes in the Restart field of the event and logs a warning.
+// With WithFailOnRestartRequired such a reload fails with ErrRestartRequired instead.
+//
+// Example:
+//
+//	cfg := &Config{}
+//	err := gocfg.Watch(ctx, "config.yaml", cfg, func(event gocfg.Event) {
+//		if event.Err != nil {
+//			log.Printf("failed to reload configuration: %v", event.Err)
+//			return
+//		}
+//		log.Printf("configuration reloaded, mode: %s", cfg.Mode)
+//	})
+//	if err != nil {
+//		log.Fatalf("failed to load configuration: %v", err)
+//	}
+//
+// The content of cfg is replaced in the background, so it must not be read concurrently with a reload,
+// e.g. only from onChange. Use WatchValue to share the configuration between goroutines.
+func Watch(ctx context.Context, path string, cfg any, onChange func(Event), opts ...Option) error {
+	return reload.Watch(ctx, path, cfg, onChange, internalOptions(opts)...)
+}
@@ -0,0 +1,47 @@
+package gocfg
+
+import (
+	"github.com/dsbasko/go-cfg/internal/file"
+	"github.com/dsbasko/go-cfg/internal/schema"
+)
+
+var (
+	// ErrInvalidRule is the error of JSONSchema for a validate tag that cannot be parsed.
+	ErrInvalidRule = schema.ErrInvalidRule
+
+	// ErrSchemaViolation is the error of ReadFile with WithSchemaValidation for values that do not match the schema.
+	ErrSchemaViolation = file.ErrSchemaViolation
+)
+
+// JSONSchema is a function that generates a JSON Schema, Draft 2020-12, of the YAML configuration files of the
+// provided cfg structure, which editors and CI can validate the files with. The keys are the same ReadFile reads,
+// including the canonical keys and the naming convention set with WithNaming. The types follow the kinds of the
+// fields and nested structs are nested objects. The default and description tags become the default and
+// description keywords, and the validate tag maps to the validation keywords:
+//
+//	required     the key must be present (required)
+//	min=N        the lower bound of a number, or of the length of a string, a slice or a map (minimum, minLength, ...)
+//	max=N        the upper bound, the same way (maximum, maxLength, ...)
+//	oneof=a b c  the value must be one of the values separated by spaces (enum)
+//
+// Other rules, e.g. email or gte=1, and the rules after dive are skipped, so the tag can be shared with a validation
+// library. The defaults of the secrets, see Secret, are left out. The function returns ErrInvalidRule if the bound of
+// a min or max rule is not a number.
+//
+// Example:
+//
+//	type Config struct {
+//		Mode string `yaml:"mode" default:"dev" validate:"oneof=dev prod"`
+//		HTTP struct {
+//			Port int `yaml:"port" validate:"required,min=1,max=65535" description:"HTTP port"`
+//		} `yaml:"http"`
+//	}
+//
+//	data, err := gocfg.JSONSchema(&Config{})
+//	if err != nil {
+//		log.Fatalf("failed to generate schema: %v", err)
+//	}
+//	os.WriteFile("config.schema.json", data, 0o644)
+func JSONSchema(cfg any, opts ...Option) ([]byte, error) {
+	return schema.Generate(cfg, internalOptions(opts)...)
+}
@@ -0,0 +1,25 @@
+package gocfg
+
+import (
+	"github.com/dsbasko/go-cfg/internal/secret"
+)
+
+// Secret is a string that holds a secret, e.g. a password or a token. It is read from all the sources like
+// any other string, but it prints as "[REDACTED]": it implements fmt.Stringer, fmt.GoStringer,
+// encoding.TextMarshaler, json.Marshaler and slog.LogValuer, so it does not leak when the configuration is
+// logged or dumped. Reveal returns the actual value.
+//
+// Fields of other types can be marked as secrets with the secret:"true" tag, which also covers all the fields
+// of a nested struct. The values of the secrets are redacted by Explain, in the changes of the reload events
+// and in the error messages.
+//
+// Example:
+//
+//	type Config struct {
+//		DSN      gocfg.Secret `yaml:"dsn" env:"DSN"`
+//		APIToken string       `yaml:"api_token" env:"API_TOKEN" secret:"true"`
+//	}
+//
+//	slog.Info("configuration loaded", "config", cfg) // ... DSN:[REDACTED] ...
+//	db, err := sql.Open("postgres", cfg.DSN.Reveal())
+type Secret = secret.Secret
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	gocfg "github.com/dsbasko/go-cfg"
+)
+
+type reloadStruct struct {
+	Mode string `yaml:"mode" default:"dev"`
+	Port int    `yaml:"port" default:"80"`
+}
+
+func Test_WatchValue(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte("mode: prod\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan gocfg.Event, 1)
+	value, err := gocfg.WatchValue[reloadStruct](
+		ctx,
+		filePath,
+		func(event gocfg.Event) { events <- event },
+		gocfg.WithSources(gocfg.SourceFile),
+		gocfg.WithPollInterval(10*time.Millisecond),
+	)
+	assert.NoError(t, err)
+
+	first := value.Load()
+	assert.Equal(t, &reloadStruct{Mode: "prod", Port: 80}, first)
+	origin, _ := value.Explain().Lookup("Port")
+	assert.Equal(t, "default", origin.Source)
+
+	portChanges := make(chan gocfg.Event, 1)
+	value.OnChange("Port", func(event gocfg.Event) { portChanges <- event })
+	value.OnChange("Mode", func(event gocfg.Event) { t.Errorf("unexpected mode change: %v", event.Changes) })
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					cfg := value.Load()
+					assert.True(t, cfg.Port == 80 || cfg.Port == 8080)
+				}
+			}
+		}()
+	}
+
+	// The file is replaced at once, so the watcher never reads a partially written file.
+	assert.NoError(t, os.WriteFile(filePath+".tmp", []byte("mode: prod\nport: 8080\n"), 0o600))
+	assert.NoError(t, os.Rename(filePath+".tmp", filePath))
+	select {
+	case event := <-events:
+		assert.NoError(t, event.Err)
+		assert.Equal(t, []gocfg.Change{{Path: "Port", Old: 80, New: 8080}}, event.Changes)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload event")
+	}
+	assert.Equal(t, []gocfg.Change{{Path: "Port", Old: 80, New: 8080}}, (<-portChanges).Changes)
+	close(done)
+	wg.Wait()
+
+	assert.Equal(t, &reloadStruct{Mode: "prod", Port: 8080}, value.Load())
+	origin, _ = value.Explain().Lookup("Port")
+	assert.Equal(t, gocfg.Origin{Path: "Port", Source: "file", Location: filePath + ":2", Value: "8080"}, origin)
+	assert.Equal(t, &reloadStruct{Mode: "prod", Port: 80}, first)
+
+	_, err = gocfg.WatchValue[string](ctx, filePath, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package gocfg
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/dsbasko/go-cfg/internal/reload"
+)
+
+// Value holds the current configuration of type T, which is replaced as a whole on every reload.
+// Load is safe for concurrent use, and the returned configuration is a fully loaded and validated
+// snapshot that is never changed afterwards, so request handlers can read it while the configuration
+// is reloaded.
+//
+// Example:
+//
+//	value, err := gocfg.WatchValue[Config](ctx, "config.yaml", nil)
+//	if err != nil {
+//		log.Fatalf("failed to load configuration: %v", err)
+//	}
+//
+//	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+//		cfg := value.Load()
+//		fmt.Fprintf(w, "mode: %s", cfg.Mode)
+//	})
+type Value[T any] struct {
+	ptr         atomic.Pointer[snapshot[T]]
+	subscribers reload.Subscribers
+}
+
+// snapshot is a published configuration together with the origins of its fields.
+type snapshot[T any] struct {
+	cfg        *T
+	provenance Provenance
+}
+
+// Load returns the current configuration. It returns nil if no configuration has been published yet.
+func (v *Value[T]) Load() *T {
+	if s := v.ptr.Load(); s != nil {
+		return s.cfg
+	}
+	return nil
+}
+
+// Explain returns the origins of the fields of the current configuration, see the Explain function. It
+// returns nil if no configuration has been published yet.
+func (v *Value[T]) Explain() Provenance {
+	if s := v.ptr.Load(); s != nil {
+		return s.provenance
+	}
+	return nil
+}
+
+// OnChange subscribes fn to the reloads that change a field in the subtree of the prefix, see the OnChange
+// function. It lets every component react only to its part of the configuration.
+//
+// Example:
+//
+//	value.OnChange("DB", func(event gocfg.Event) {
+//		pool.Reconfigure(value.Load().DB)
+//	})
+func (v *Value[T]) OnChange(prefix string, fn func(Event)) {
+	v.subscribers.Add(prefix, fn)
+}
+
+// WatchValue is similar to Watch, but every configuration, including the initial one, is loaded into a
+// new structure of type T and published into the returned Value. A failed reload keeps the previous
+// configuration in the Value. The function returns an error if the initial load fails.
+func WatchValue[T any](ctx context.Context, path string, onChange func(Event), opts ...Option) (*Value[T], error) {
+	value := &Value[T]{}
+	publish := func(cfg any, provenance reload.Provenance) {
+		value.ptr.Store(&snapshot[T]{cfg: cfg.(*T), provenance: provenance})
+	}
+
+	typeOf := reflect.TypeOf((*T)(nil)).Elem()
+	notify := func(event Event) {
+		value.subscribers.Notify(event)
+		if onChange != nil {
+			onChange(event)
+		}
+	}
+
+	if err := reload.Publish(ctx, path, typeOf, publish, notify, internalOptions(opts)...); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}